@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2"
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/cli/go-gh/v2/pkg/config"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// accountState holds the account selected for each host with --user or the
+// account picker, and the token gh stores for it. Hosts without an entry use
+// gh's active account.
+var accountState = struct {
+	sync.Mutex
+	logins map[string]string
+	tokens map[string]string
+}{
+	logins: map[string]string{},
+	tokens: map[string]string{},
+}
+
+// selectAccount chooses the gh account used for hostname. --user names it
+// directly; otherwise, when several accounts are logged in to the host, the
+// user picks one interactively with the active account preselected.
+func selectAccount(hostname string) error {
+	accounts, active := ghAccounts(hostname)
+
+	login := strings.TrimSpace(opts.user)
+	if _, source := auth.TokenForHost(hostname); strings.HasSuffix(source, "_TOKEN") {
+		if login != "" {
+			return fmt.Errorf("--user cannot be used while %s is set; the token from the environment is always used", source)
+		}
+		return nil
+	}
+	if login == "" {
+		if len(accounts) < 2 || noPrompts || !term.IsTerminal(os.Stdin) || !term.IsTerminal(os.Stdout) {
+			return nil
+		}
+		var err error
+		login, err = promptSelect(fmt.Sprintf("Select account for %s", hostname), accounts, active)
+		if err != nil {
+			return err
+		}
+	}
+
+	index := slices.IndexFunc(accounts, func(account string) bool { return strings.EqualFold(account, login) })
+	if index < 0 {
+		if len(accounts) == 0 {
+			return fmt.Errorf("no accounts are logged in to %s (run: gh auth login -h %s)", hostname, hostname)
+		}
+		return fmt.Errorf("account %s is not logged in to %s (logged in: %s)", login, hostname, strings.Join(accounts, ", "))
+	}
+	login = accounts[index]
+	opts.user = login
+	if login == active {
+		return nil
+	}
+
+	stdout, stderr, err := gh.Exec("auth", "token", "--hostname", hostname, "--user", login)
+	if err != nil {
+		return fmt.Errorf("failed to read the token for %s on %s: %w (%s)", login, hostname, err, strings.TrimSpace(stderr.String()))
+	}
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return fmt.Errorf("gh has no token for %s on %s", login, hostname)
+	}
+
+	accountState.Lock()
+	accountState.logins[hostname] = login
+	accountState.tokens[hostname] = token
+	accountState.Unlock()
+	// Clients created from now on authenticate as the selected account
+	resetClients(hostname)
+	return nil
+}
+
+// ghAccounts returns the accounts logged in to hostname in gh's hosts
+// configuration and the active one
+func ghAccounts(hostname string) ([]string, string) {
+	cfg, err := config.Read(nil)
+	if err != nil {
+		return nil, ""
+	}
+	active, _ := cfg.Get([]string{"hosts", hostname, "user"})
+	accounts, _ := cfg.Keys([]string{"hosts", hostname, "users"})
+	if len(accounts) == 0 && active != "" {
+		accounts = []string{active}
+	}
+	slices.Sort(accounts)
+	return accounts, active
+}
+
+// completeAccounts offers the accounts logged in to the last --hostname host
+func completeAccounts(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	hostnames, _ := cmd.Flags().GetStringArray("hostname")
+	hostname, _ := ghDefaultHost()
+	if len(hostnames) > 0 {
+		hostname = hostnames[len(hostnames)-1]
+	}
+	accounts, _ := ghAccounts(normalizeHostname(hostname))
+	return accounts, cobra.ShellCompDirectiveNoFileComp
+}
+
+// accountToken returns the token of the account selected for hostname, or
+// an empty string to use gh's active account
+func accountToken(hostname string) string {
+	accountState.Lock()
+	defer accountState.Unlock()
+	return accountState.tokens[hostname]
+}
+
+// currentAccount returns the login the API requests to hostname run as
+func currentAccount(hostname string) (string, error) {
+	accountState.Lock()
+	login := accountState.logins[hostname]
+	accountState.Unlock()
+	if login != "" {
+		return login, nil
+	}
+
+	client, err := restClient(hostname)
+	if err != nil {
+		return "", err
+	}
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := client.Get("user", &user); err != nil {
+		return "", err
+	}
+	if user.Login == "" {
+		return "", errors.New("the API did not return a login")
+	}
+
+	accountState.Lock()
+	accountState.logins[hostname] = user.Login
+	accountState.Unlock()
+	return user.Login, nil
+}
+
+// printAccount shows which account a confirmed run will act as
+func printAccount(hostname string) {
+	login, err := currentAccount(hostname)
+	if err != nil {
+		pterm.Warning.Printfln("Could not determine the account for %s: %v", hostname, err)
+		return
+	}
+	pterm.Info.Printfln("Account: %s (%s)", login, hostname)
+}
@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/config"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage shortcuts for commonly used commands and flags",
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <name> <expansion>",
+	Short: "Create or replace an alias",
+	Example: `  gh custom-roles alias set prod-create "create --hostname ghes.example.com --enterprise acme --concurrency 10"
+  gh custom-roles prod-create --role-name Developer`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAliasSet,
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List aliases",
+	Args:  cobra.NoArgs,
+	RunE:  runAliasList,
+}
+
+var aliasDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete an alias",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAliasDelete,
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasSetCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	aliasCmd.AddCommand(aliasDeleteCmd)
+}
+
+func aliasesPath() string {
+	return filepath.Join(config.ConfigDir(), "gh-custom-roles", "aliases.json")
+}
+
+// loadAliases reads the saved aliases, returning none when the file does not
+// exist yet
+func loadAliases() (map[string]string, error) {
+	aliases := map[string]string{}
+	data, err := os.ReadFile(aliasesPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return aliases, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read aliases: %w", err)
+	}
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", aliasesPath(), err)
+	}
+	return aliases, nil
+}
+
+func saveAliases(aliases map[string]string) error {
+	data, err := json.MarshalIndent(aliases, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := aliasesPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to save aliases: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save aliases: %w", err)
+	}
+	return nil
+}
+
+func runAliasSet(_ *cobra.Command, args []string) error {
+	name := strings.TrimSpace(args[0])
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("invalid alias name %q", args[0])
+	}
+	if isCommandName(name) {
+		return fmt.Errorf("%q is already a command and cannot be used as an alias", name)
+	}
+
+	expansion, err := splitArgs(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid expansion: %w", err)
+	}
+	if len(expansion) == 0 || !isCommandName(expansion[0]) || expansion[0] == aliasCmd.Name() {
+		return fmt.Errorf("expansion must start with a command such as create, assign, or stats")
+	}
+
+	aliases, err := loadAliases()
+	if err != nil {
+		return err
+	}
+	_, replaced := aliases[name]
+	aliases[name] = strings.TrimSpace(args[1])
+	if err := saveAliases(aliases); err != nil {
+		return err
+	}
+
+	if replaced {
+		pterm.Success.Printfln("Replaced alias %s: %s", name, aliases[name])
+	} else {
+		pterm.Success.Printfln("Added alias %s: %s", name, aliases[name])
+	}
+	return nil
+}
+
+func runAliasList(_ *cobra.Command, _ []string) error {
+	aliases, err := loadAliases()
+	if err != nil {
+		return err
+	}
+	if len(aliases) == 0 {
+		pterm.Info.Println("No aliases configured.")
+		return nil
+	}
+
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	data := pterm.TableData{{"Alias", "Expansion"}}
+	for _, name := range names {
+		data = append(data, []string{name, aliases[name]})
+	}
+	return pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+}
+
+func runAliasDelete(_ *cobra.Command, args []string) error {
+	aliases, err := loadAliases()
+	if err != nil {
+		return err
+	}
+	if _, ok := aliases[args[0]]; !ok {
+		return fmt.Errorf("no alias named %q", args[0])
+	}
+	delete(aliases, args[0])
+	if err := saveAliases(aliases); err != nil {
+		return err
+	}
+	pterm.Success.Printfln("Deleted alias %s", args[0])
+	return nil
+}
+
+// isCommandName reports whether name is a built-in command or one of its
+// aliases
+func isCommandName(name string) bool {
+	if name == "help" || name == "completion" {
+		return true
+	}
+	for _, command := range rootCmd.Commands() {
+		if command.Name() == name || command.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandAlias replaces the alias in args with its saved expansion. The alias
+// is the first argument after any global flags, as in
+// "--hostname ghes.example.com prod-create". Arguments after the alias are
+// appended, so they can add to or override the flags in the expansion.
+func expandAlias(args []string) ([]string, error) {
+	i := commandIndex(args)
+	if i == len(args) || isCommandName(args[i]) {
+		return args, nil
+	}
+	aliases, err := loadAliases()
+	if err != nil {
+		return nil, err
+	}
+	expansion, ok := aliases[args[i]]
+	if !ok {
+		return args, nil
+	}
+	expanded, err := splitArgs(expansion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alias %s: %w", args[i], err)
+	}
+	// --hostname on the command line replaces the alias's hosts instead of
+	// adding to them
+	if setsHostname(args[:i]) || setsHostname(args[i+1:]) {
+		expanded = dropHostnames(expanded)
+	}
+	return slices.Concat(args[:i], expanded, args[i+1:]), nil
+}
+
+// hostnameArgs returns how many arguments starting at args[i] set
+// --hostname: 2 for "--hostname HOST" and "-u HOST", 1 for "--hostname=HOST"
+// and "-uHOST", and 0 for any other argument
+func hostnameArgs(args []string, i int) int {
+	arg := args[i]
+	switch {
+	case arg == "--hostname" || arg == "-u":
+		return min(2, len(args)-i)
+	case strings.HasPrefix(arg, "--hostname="), strings.HasPrefix(arg, "-u"):
+		return 1
+	}
+	return 0
+}
+
+// setsHostname reports whether args set --hostname before any "--"
+func setsHostname(args []string) bool {
+	for i, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if hostnameArgs(args, i) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// dropHostnames removes every --hostname flag and its value from args
+func dropHostnames(args []string) []string {
+	var kept []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			return append(kept, args[i:]...)
+		}
+		if n := hostnameArgs(args, i); n > 0 {
+			i += n - 1
+			continue
+		}
+		kept = append(kept, args[i])
+	}
+	return kept
+}
+
+// commandIndex returns the index of the first argument that is not a global
+// flag or a global flag's value, or len(args) when there is none
+func commandIndex(args []string) int {
+	flags := rootCmd.PersistentFlags()
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || !strings.HasPrefix(arg, "-") || arg == "-" {
+			return i
+		}
+		if strings.Contains(arg, "=") {
+			continue
+		}
+		var flag *pflag.Flag
+		if name, ok := strings.CutPrefix(arg, "--"); ok {
+			flag = flags.Lookup(name)
+		} else if len(arg) == 2 {
+			// A longer -uVALUE carries its value
+			flag = flags.ShorthandLookup(arg[1:])
+		}
+		if flag != nil && flag.NoOptDefVal == "" {
+			i++
+		}
+	}
+	return len(args)
+}
+
+// splitArgs splits an alias expansion into arguments the way a POSIX shell
+// would: single quotes are literal, double quotes allow backslash escapes
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == '\\':
+			escaped = true
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
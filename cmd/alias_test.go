@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDropHostnames(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "separate value", args: []string{"create", "--hostname", "ghes.example.com", "--enterprise", "acme"}, want: []string{"create", "--enterprise", "acme"}},
+		{name: "shorthand", args: []string{"create", "-u", "ghes.example.com", "-u", "github.com"}, want: []string{"create"}},
+		{name: "inline value", args: []string{"--hostname=ghes.example.com", "create", "-ugithub.com"}, want: []string{"create"}},
+		{name: "after terminator", args: []string{"create", "--", "--hostname", "x"}, want: []string{"create", "--", "--hostname", "x"}},
+		{name: "other flags", args: []string{"create", "--user", "admin"}, want: []string{"create", "--user", "admin"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dropHostnames(tt.args); !slices.Equal(got, tt.want) {
+				t.Errorf("dropHostnames(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetsHostname(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{args: []string{"--hostname", "github.com"}, want: true},
+		{args: []string{"-ugithub.com", "--role-name", "Developer"}, want: true},
+		{args: []string{"--hostname=github.com"}, want: true},
+		{args: []string{"--role-name", "Developer"}, want: false},
+		{args: []string{"--", "--hostname", "github.com"}, want: false},
+		{args: nil, want: false},
+	}
+	for _, tt := range tests {
+		if got := setsHostname(tt.args); got != tt.want {
+			t.Errorf("setsHostname(%q) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,672 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// orgRole is a custom role together with the organization that defines it
+type orgRole struct {
+	Org  string
+	Role customRole
+}
+
+// roleDefinition is a distinct combination of base role and permissions,
+// with every role across the organizations that has exactly that definition
+type roleDefinition struct {
+	BaseRole    string
+	Permissions []string
+	Roles       []orgRole
+}
+
+// baseRoleRank orders base roles by the access they grant
+var baseRoleRank = map[string]int{"read": 1, "triage": 2, "write": 3, "maintain": 4}
+
+// nearDuplicateDistance is the default number of permissions two role
+// definitions may differ by and still be reported as near-duplicates
+const nearDuplicateDistance = 1
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Analyze custom roles across organizations for rarely granted, redundant, and unused permissions",
+	RunE:  runAnalyze,
+}
+
+func init() {
+	// Analyze command flags
+	analyzeCmd.Flags().BoolVar(&opts.findDuplicates, "duplicates", false, "Report roles with identical or nearly identical definitions under different names")
+	analyzeCmd.Flags().BoolVar(&opts.findCollisions, "collisions", false, "Report role names defined differently in different organizations")
+	analyzeCmd.Flags().BoolVar(&opts.findStale, "stale-permissions", false, "Report roles granting permissions that are no longer in the host's permission catalog")
+	analyzeCmd.Flags().IntVar(&opts.maxDifference, "max-difference", nearDuplicateDistance, "Permissions near-duplicate roles may differ by (with --duplicates)")
+}
+
+func runAnalyze(_ *cobra.Command, _ []string) error {
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	if err := selectTargets(); err != nil {
+		return err
+	}
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, opts.allOrgs); err != nil {
+		return err
+	}
+
+	if err := resolveEnterprise(); err != nil {
+		return err
+	}
+
+	orgs, err := resolveOrganizations(opts)
+	if err != nil {
+		return err
+	}
+	if len(orgs) == 0 {
+		return errors.New("no organizations provided")
+	}
+
+	// Validate concurrency and delay bounds
+	if err := validatePacing(opts); err != nil {
+		return err
+	}
+	if opts.maxDifference < 0 {
+		return fmt.Errorf("max difference must be non-negative (got %d)", opts.maxDifference)
+	}
+
+	inventory, err := collectCustomRoles(opts, orgs)
+	if err != nil {
+		return err
+	}
+	roles, errorCount := inventory.Roles, inventory.ErrorCount
+	definitions := groupRoleDefinitions(roles)
+
+	var clusters [][]*roleDefinition
+	var collisions []nameCollision
+	var unused []fineGrainedPermission
+	var catalog []fineGrainedPermission
+	var stale []staleDefinition
+	if opts.findDuplicates {
+		clusters = duplicateClusters(definitions, opts.maxDifference)
+		if err := printDuplicateClusters(clusters); err != nil {
+			return err
+		}
+	}
+	if opts.findCollisions {
+		collisions = nameCollisions(roles)
+		if err := printNameCollisions(collisions); err != nil {
+			return err
+		}
+	}
+	fullReport := !opts.findDuplicates && !opts.findCollisions && !opts.findStale
+	if fullReport || opts.findStale {
+		catalog, err = listFineGrainedPermissions(opts.hostname, orgs[0])
+		if err != nil {
+			return err
+		}
+		stale = stalePermissions(catalog, definitions)
+	}
+	if fullReport {
+		if err := printRarelyGrantedPermissions(definitions); err != nil {
+			return err
+		}
+		if err := printSupersetRoles(definitions); err != nil {
+			return err
+		}
+		unused = unusedPermissions(catalog, definitions)
+		printUnusedPermissions(unused)
+	}
+	if fullReport || opts.findStale {
+		if err := printStalePermissions(stale); err != nil {
+			return err
+		}
+	}
+
+	// Display summary
+	pterm.Println()
+	pterm.DefaultSection.Println("Summary")
+	pterm.Info.Printfln("✓ Custom roles analyzed: %d (%d distinct definitions)", len(roles), len(definitions))
+	if opts.findDuplicates {
+		pterm.Info.Printfln("Duplicate role groups: %d", len(clusters))
+	}
+	if opts.findCollisions {
+		if len(collisions) > 0 {
+			pterm.Warning.Printfln("⚠ Role names with conflicting definitions: %d", len(collisions))
+		} else {
+			pterm.Info.Println("Role names with conflicting definitions: 0")
+		}
+	}
+	if fullReport {
+		pterm.Info.Printfln("Unused permissions: %d of %d", len(unused), len(catalog))
+	}
+	if fullReport || opts.findStale {
+		if staleRoles := countStaleRoles(stale); staleRoles > 0 {
+			pterm.Warning.Printfln("⚠ Roles granting unavailable permissions: %d", staleRoles)
+		} else {
+			pterm.Info.Println("Roles granting unavailable permissions: 0")
+		}
+	}
+	if errorCount > 0 {
+		pterm.Error.Printfln("✗ Errors: %d", errorCount)
+	}
+
+	// Display command for replication
+	printReplicationTip("this analysis", buildAnalyzeReplicationCommand(opts))
+
+	if errorCount > 0 {
+		return fmt.Errorf("completed with %d errors", errorCount)
+	}
+	return nil
+}
+
+// roleInventory is the custom roles read from a set of organizations
+type roleInventory struct {
+	Roles []orgRole
+	// Orgs are the organizations whose roles were read, with or without
+	// custom roles
+	Orgs       []string
+	ErrorCount int
+	// Unmanaged counts the roles left out by --managed-only
+	Unmanaged int
+}
+
+// collectCustomRoles reads the custom roles of every organization. Missing
+// organizations are skipped with a warning; other failures are reported and
+// counted as errors. With --managed-only, roles without the managed marker
+// are left out and only counted.
+func collectCustomRoles(opts options, orgs []string) (roleInventory, error) {
+	var inventory roleInventory
+
+	progressBar, err := startProgressbar(len(orgs), "Reading custom roles")
+	if err != nil {
+		return inventory, err
+	}
+	defer progressBar.Stop()
+
+	var mu sync.Mutex
+	processTargets(opts, orgs, func(org string) {
+		found, listErr := listCustomRoles(opts.hostname, org)
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case listErr != nil && isNotFoundError(listErr):
+			pterm.Warning.Printfln("Organization %s not found. Skipping.", org)
+		case listErr != nil:
+			pterm.Error.Printfln("Failed to read custom roles for %s: %v", org, listErr)
+			inventory.ErrorCount++
+		default:
+			inventory.Orgs = append(inventory.Orgs, org)
+			for _, role := range found {
+				if excludedByManagedOnly(role) {
+					inventory.Unmanaged++
+					continue
+				}
+				inventory.Roles = append(inventory.Roles, orgRole{Org: org, Role: role})
+			}
+		}
+		progressBar.Increment()
+	})
+	progressBar.Stop()
+
+	sort.Strings(inventory.Orgs)
+	sort.Slice(inventory.Roles, func(i, j int) bool {
+		a, b := inventory.Roles[i], inventory.Roles[j]
+		if a.Org != b.Org {
+			return a.Org < b.Org
+		}
+		return a.Role.Name < b.Role.Name
+	})
+	return inventory, nil
+}
+
+// groupRoleDefinitions groups roles with the same base role and permission
+// set, ordered by how many roles share each definition
+func groupRoleDefinitions(roles []orgRole) []*roleDefinition {
+	byKey := map[string]*roleDefinition{}
+	var definitions []*roleDefinition
+	for _, role := range roles {
+		permissions := uniqueStrings(append([]string(nil), role.Role.Permissions...))
+		sort.Strings(permissions)
+		key := role.Role.BaseRole + ":" + strings.Join(permissions, ",")
+		definition, ok := byKey[key]
+		if !ok {
+			definition = &roleDefinition{BaseRole: role.Role.BaseRole, Permissions: permissions}
+			byKey[key] = definition
+			definitions = append(definitions, definition)
+		}
+		definition.Roles = append(definition.Roles, role)
+	}
+
+	sort.SliceStable(definitions, func(i, j int) bool {
+		return len(definitions[i].Roles) > len(definitions[j].Roles)
+	})
+	return definitions
+}
+
+// includes reports whether definition d grants at least everything other
+// grants
+func (d *roleDefinition) includes(other *roleDefinition) bool {
+	if baseRoleRank[d.BaseRole] < baseRoleRank[other.BaseRole] {
+		return false
+	}
+	granted := map[string]bool{}
+	for _, permission := range d.Permissions {
+		granted[permission] = true
+	}
+	for _, permission := range other.Permissions {
+		if !granted[permission] {
+			return false
+		}
+	}
+	return true
+}
+
+// describeRoles names the roles sharing a definition, with the number of
+// organizations using each name, e.g. "Developer (12 orgs), Dev (1 org)"
+func describeRoles(roles []orgRole) string {
+	counts := map[string]int{}
+	var names []string
+	for _, role := range roles {
+		if counts[role.Role.Name] == 0 {
+			names = append(names, role.Role.Name)
+		}
+		counts[role.Role.Name]++
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		return counts[names[i]] > counts[names[j]]
+	})
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s (%s)", name, pluralize(counts[name], "org", "orgs")))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func pluralize(count int, singular, plural string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, singular)
+	}
+	return fmt.Sprintf("%d %s", count, plural)
+}
+
+// printRarelyGrantedPermissions lists permissions granted by only one role
+// definition
+func printRarelyGrantedPermissions(definitions []*roleDefinition) error {
+	grantedBy := map[string][]*roleDefinition{}
+	for _, definition := range definitions {
+		for _, permission := range definition.Permissions {
+			grantedBy[permission] = append(grantedBy[permission], definition)
+		}
+	}
+
+	var permissions []string
+	for permission, granting := range grantedBy {
+		if len(granting) == 1 {
+			permissions = append(permissions, permission)
+		}
+	}
+	sort.Strings(permissions)
+
+	pterm.Println()
+	pterm.DefaultSection.Println("Permissions granted by only one role")
+	if len(permissions) == 0 {
+		pterm.Info.Println("Every granted permission is granted by more than one role definition.")
+		return nil
+	}
+	data := pterm.TableData{{"Permission", "Role"}}
+	for _, permission := range permissions {
+		data = append(data, []string{permission, describeRoles(grantedBy[permission][0].Roles)})
+	}
+	return pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+}
+
+// printSupersetRoles lists role definitions that grant everything another
+// definition grants, making the smaller role a candidate for removal
+func printSupersetRoles(definitions []*roleDefinition) error {
+	data := pterm.TableData{{"Role", "Includes everything in"}}
+	for _, definition := range definitions {
+		for _, other := range definitions {
+			if other == definition || !definition.includes(other) {
+				continue
+			}
+			data = append(data, []string{describeRoles(definition.Roles), describeRoles(other.Roles)})
+		}
+	}
+
+	pterm.Println()
+	pterm.DefaultSection.Println("Roles that are supersets of other roles")
+	if len(data) == 1 {
+		pterm.Info.Println("No role grants a superset of another role.")
+		return nil
+	}
+	return pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+}
+
+// unusedPermissions returns the catalog permissions no role grants
+func unusedPermissions(catalog []fineGrainedPermission, definitions []*roleDefinition) []fineGrainedPermission {
+	granted := map[string]bool{}
+	for _, definition := range definitions {
+		for _, permission := range definition.Permissions {
+			granted[permission] = true
+		}
+	}
+
+	var unused []fineGrainedPermission
+	for _, permission := range catalog {
+		if !granted[permission.Name] {
+			unused = append(unused, permission)
+		}
+	}
+	sort.Slice(unused, func(i, j int) bool {
+		return unused[i].Name < unused[j].Name
+	})
+	return unused
+}
+
+// staleDefinition is a role definition granting permissions that are not
+// in the host's current permission catalog
+type staleDefinition struct {
+	Definition  *roleDefinition
+	Unavailable []string
+}
+
+// stalePermissions finds role definitions granting permissions the catalog
+// no longer offers, such as after a GHES downgrade or a retired feature
+func stalePermissions(catalog []fineGrainedPermission, definitions []*roleDefinition) []staleDefinition {
+	available := map[string]bool{}
+	for _, permission := range catalog {
+		available[permission.Name] = true
+	}
+
+	var stale []staleDefinition
+	for _, definition := range definitions {
+		var unavailable []string
+		for _, permission := range definition.Permissions {
+			if !available[permission] {
+				unavailable = append(unavailable, permission)
+			}
+		}
+		if len(unavailable) > 0 {
+			stale = append(stale, staleDefinition{Definition: definition, Unavailable: unavailable})
+		}
+	}
+	return stale
+}
+
+// countStaleRoles returns the number of roles across organizations with a
+// stale definition
+func countStaleRoles(stale []staleDefinition) int {
+	count := 0
+	for _, definition := range stale {
+		count += len(definition.Definition.Roles)
+	}
+	return count
+}
+
+func printStalePermissions(stale []staleDefinition) error {
+	pterm.Println()
+	pterm.DefaultSection.Println("Roles granting unavailable permissions")
+	if len(stale) == 0 {
+		pterm.Info.Println("Every granted permission is in the host's permission catalog.")
+		return nil
+	}
+	data := pterm.TableData{{"Role", "Unavailable permissions", "Organizations"}}
+	for _, definition := range stale {
+		orgs := make([]string, 0, len(definition.Definition.Roles))
+		for _, role := range definition.Definition.Roles {
+			orgs = append(orgs, role.Org)
+		}
+		data = append(data, []string{describeRoles(definition.Definition.Roles), strings.Join(definition.Unavailable, ", "), previewList(orgs)})
+	}
+	return pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+}
+
+func printUnusedPermissions(unused []fineGrainedPermission) {
+	pterm.Println()
+	pterm.DefaultSection.Println("Permissions never granted")
+	if len(unused) == 0 {
+		pterm.Info.Println("Every available permission is granted by at least one role.")
+		return
+	}
+	for _, permission := range unused {
+		pterm.Printfln("  %s - %s", permission.Name, permission.Description)
+	}
+}
+
+func buildAnalyzeReplicationCommand(opts options) string {
+	cmd := "gh custom-roles analyze"
+
+	if opts.hostname != "" {
+		cmd += " --hostname " + shellQuote(opts.hostname)
+	}
+	cmd += targetFlags(opts)
+	if opts.findDuplicates {
+		cmd += " --duplicates"
+		if opts.maxDifference != nearDuplicateDistance {
+			cmd += fmt.Sprintf(" --max-difference %d", opts.maxDifference)
+		}
+	}
+	if opts.findCollisions {
+		cmd += " --collisions"
+	}
+	if opts.findStale {
+		cmd += " --stale-permissions"
+	}
+	cmd += pacingFlags(opts)
+
+	return cmd
+}
+
+// permissionDistance returns the number of permissions granted by only one
+// of two definitions, or -1 when their base roles differ
+func permissionDistance(a, b *roleDefinition) int {
+	if a.BaseRole != b.BaseRole {
+		return -1
+	}
+	inA := map[string]bool{}
+	for _, permission := range a.Permissions {
+		inA[permission] = true
+	}
+	distance := 0
+	for _, permission := range b.Permissions {
+		if inA[permission] {
+			delete(inA, permission)
+		} else {
+			distance++
+		}
+	}
+	return distance + len(inA)
+}
+
+// duplicateClusters groups definitions that are within maxDifference
+// permissions of each other (transitively) and keeps the groups whose roles
+// go by more than one name. The largest definition of each group comes first.
+func duplicateClusters(definitions []*roleDefinition, maxDifference int) [][]*roleDefinition {
+	parent := make([]int, len(definitions))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range definitions {
+		for j := i + 1; j < len(definitions); j++ {
+			if distance := permissionDistance(definitions[i], definitions[j]); distance >= 0 && distance <= maxDifference {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	groups := map[int][]*roleDefinition{}
+	var roots []int
+	for i, definition := range definitions {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], definition)
+	}
+
+	var clusters [][]*roleDefinition
+	for _, root := range roots {
+		cluster := groups[root]
+		names := map[string]bool{}
+		for _, definition := range cluster {
+			for _, role := range definition.Roles {
+				names[roleNameKey(role.Role.Name)] = true
+			}
+		}
+		if len(names) > 1 {
+			clusters = append(clusters, cluster)
+		}
+	}
+	return clusters
+}
+
+// canonicalName returns the most widely used role name in a cluster
+func canonicalName(cluster []*roleDefinition) string {
+	counts := map[string]int{}
+	best := ""
+	for _, definition := range cluster {
+		for _, role := range definition.Roles {
+			counts[role.Role.Name]++
+			if best == "" || counts[role.Role.Name] > counts[best] || (counts[role.Role.Name] == counts[best] && role.Role.Name < best) {
+				best = role.Role.Name
+			}
+		}
+	}
+	return best
+}
+
+func printDuplicateClusters(clusters [][]*roleDefinition) error {
+	pterm.Println()
+	pterm.DefaultSection.Println("Duplicate roles")
+	if len(clusters) == 0 {
+		pterm.Info.Println("No roles share a definition under different names.")
+		return nil
+	}
+
+	for i, cluster := range clusters {
+		// Definitions are ordered by how many roles use them, so the first
+		// one is the most common and is suggested as the canonical definition
+		canonical := cluster[0]
+		pterm.Println()
+		pterm.DefaultSection.WithLevel(2).Printfln("Group %d: suggested canonical role %q", i+1, canonicalName(cluster))
+		pterm.Info.Printfln("Base Role: %s", canonical.BaseRole)
+		pterm.Info.Printfln("Permissions: %s", strings.Join(canonical.Permissions, ", "))
+
+		data := pterm.TableData{{"Roles", "Differences from canonical"}}
+		for _, definition := range cluster {
+			data = append(data, []string{describeRoles(definition.Roles), describeDifference(canonical, definition)})
+		}
+		if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describeDifference lists the permissions definition adds to or lacks from
+// canonical
+func describeDifference(canonical, definition *roleDefinition) string {
+	inCanonical := map[string]bool{}
+	for _, permission := range canonical.Permissions {
+		inCanonical[permission] = true
+	}
+	var parts []string
+	for _, permission := range definition.Permissions {
+		if !inCanonical[permission] {
+			parts = append(parts, "+"+permission)
+		}
+		delete(inCanonical, permission)
+	}
+	for _, permission := range canonical.Permissions {
+		if inCanonical[permission] {
+			parts = append(parts, "-"+permission)
+		}
+	}
+	if len(parts) == 0 {
+		return "identical"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// nameCollision is a role name that organizations define in more than one way
+type nameCollision struct {
+	Name        string
+	Definitions []*roleDefinition
+}
+
+// nameCollisions finds role names that exist in several organizations with
+// different base roles or permissions. Names are compared the way GitHub
+// compares them, so "Developer" and "developer" collide.
+func nameCollisions(roles []orgRole) []nameCollision {
+	byName := map[string][]orgRole{}
+	var keys []string
+	for _, role := range roles {
+		key := roleNameKey(role.Role.Name)
+		if _, ok := byName[key]; !ok {
+			keys = append(keys, key)
+		}
+		byName[key] = append(byName[key], role)
+	}
+	sort.Strings(keys)
+
+	var collisions []nameCollision
+	for _, key := range keys {
+		definitions := groupRoleDefinitions(byName[key])
+		if len(definitions) > 1 {
+			collisions = append(collisions, nameCollision{Name: byName[key][0].Role.Name, Definitions: definitions})
+		}
+	}
+	return collisions
+}
+
+func printNameCollisions(collisions []nameCollision) error {
+	pterm.Println()
+	pterm.DefaultSection.Println("Role name collisions")
+	if len(collisions) == 0 {
+		pterm.Info.Println("Every role name means the same thing in every organization that defines it.")
+		return nil
+	}
+
+	for _, collision := range collisions {
+		pterm.Println()
+		pterm.DefaultSection.WithLevel(2).Printfln("%q has %d definitions", collision.Name, len(collision.Definitions))
+		data := pterm.TableData{{"Base Role", "Permissions", "Organizations"}}
+		for _, definition := range collision.Definitions {
+			orgs := make([]string, 0, len(definition.Roles))
+			for _, role := range definition.Roles {
+				orgs = append(orgs, role.Org)
+			}
+			data = append(data, []string{definition.BaseRole, strings.Join(definition.Permissions, ", "), previewList(orgs)})
+		}
+		if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// previewList joins up to previewSampleSize values and notes how many more
+// there are
+func previewList(values []string) string {
+	if len(values) <= previewSampleSize {
+		return strings.Join(values, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(values[:previewSampleSize], ", "), len(values)-previewSampleSize)
+}
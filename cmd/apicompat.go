@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// Feature support reported by the api-compat command
+const (
+	compatSupported    = "supported"
+	compatUnsupported  = "not supported"
+	compatNoAccess     = "no access"
+	compatNotChecked   = "not checked"
+	compatCheckFailure = "check failed"
+)
+
+// compatCheck is the outcome of probing one feature on the target host
+type compatCheck struct {
+	Feature string
+	UsedBy  string
+	Status  string
+	Details string
+}
+
+var apiCompatCmd = &cobra.Command{
+	Use:   "api-compat",
+	Short: "Report which features of this extension the target host supports",
+	Args:  cobra.NoArgs,
+	RunE:  runAPICompat,
+}
+
+func runAPICompat(_ *cobra.Command, _ []string) error {
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+	if opts.allOrgs || len(opts.orgsCSVPaths) > 0 || opts.orgsFromCmd != "" {
+		return errors.New("api-compat probes a single organization; use --org")
+	}
+	if opts.org == "" {
+		opts.org, err = promptText("Organization to probe")
+		if err != nil {
+			return err
+		}
+	}
+	org := normalizeOrg(opts.org)
+	if org == "" {
+		return errors.New("organization name is required")
+	}
+	enterprise := strings.TrimSpace(opts.enterprise)
+	if enterprise == "" {
+		enterprise = defaultEnterpriseForHost(opts.hostname)
+	}
+
+	var checks []compatCheck
+
+	// The scope check also reveals the GHES version through the response headers
+	missing, err := missingScopes(opts.hostname, enterprise != "")
+	if err != nil {
+		return err
+	}
+	scopes := compatCheck{Feature: "OAuth scopes", UsedBy: "all commands", Status: compatSupported, Details: "admin:org present"}
+	if enterprise != "" {
+		scopes.Details = "admin:org and read:enterprise present"
+	}
+	if len(missing) > 0 {
+		scopes.Status = compatNoAccess
+		scopes.Details = "missing " + strings.Join(missing, ", ")
+	}
+	checks = append(checks, scopes)
+
+	versioned := compatCheck{Feature: "Versioned REST API", UsedBy: "all commands", Status: compatSupported, Details: "X-GitHub-Api-Version " + restAPIVersion}
+	if apiVersionFor(opts.hostname) == "" {
+		versioned.Status = compatUnsupported
+		versioned.Details = "header omitted; the server default version is used"
+	}
+	checks = append(checks, versioned)
+
+	if _, err := fetchOrganizationPlan(opts.hostname, org); isNotFoundError(err) {
+		return fmt.Errorf("organization %s not found on %s", org, opts.hostname)
+	}
+
+	checks = append(checks,
+		probeREST("Custom repository roles", "create, edit, copy-permissions, analyze, stats, who-can", "orgs/"+org+"/custom-repository-roles"),
+		probeREST("Fine-grained permission catalog", "create, edit", "orgs/"+org+"/repository-fine-grained-permissions"),
+		probeREST("Organization roles", "-", "orgs/"+org+"/organization-roles"),
+		probeREST("Repository team and collaborator roles", "assign, migrate-grants, assignments", "orgs/"+org+"/repos?per_page=1"),
+	)
+
+	if enterprise == "" {
+		checks = append(checks, compatCheck{Feature: "Enterprise organization listing", UsedBy: "--all-orgs, orgs list", Status: compatNotChecked, Details: "pass --enterprise to check"})
+	} else {
+		checks = append(checks, probeEnterpriseGraphQL(opts.hostname, enterprise))
+	}
+
+	pterm.Println()
+	if version := ghesVersion(opts.hostname); version != "" {
+		pterm.DefaultSection.Printfln("%s (%s %s)", opts.hostname, hostType(opts.hostname), version)
+	} else {
+		pterm.DefaultSection.Printfln("%s (%s)", opts.hostname, hostType(opts.hostname))
+	}
+	data := pterm.TableData{{"Feature", "Used by", "Status", "Details"}}
+	unsupported := 0
+	for _, check := range checks {
+		if check.Status != compatSupported && check.Status != compatNotChecked {
+			unsupported++
+		}
+		data = append(data, []string{check.Feature, check.UsedBy, check.Status, check.Details})
+	}
+	if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+		return err
+	}
+
+	pterm.Println()
+	if unsupported > 0 {
+		pterm.Warning.Printfln("%d features are unavailable to this account on %s", unsupported, opts.hostname)
+	} else {
+		pterm.Success.Printfln("Every checked feature is available on %s", opts.hostname)
+	}
+	return nil
+}
+
+// probeREST checks a feature by reading one of its REST endpoints
+func probeREST(feature, usedBy, endpoint string) compatCheck {
+	check := compatCheck{Feature: feature, UsedBy: usedBy}
+	_, _, err := ghAPI(opts.hostname, endpoint)
+	switch {
+	case err == nil:
+		check.Status = compatSupported
+	case isNotFoundError(err):
+		check.Status = compatUnsupported
+		check.Details = "endpoint not found on this host"
+	case isPlanUnsupportedError(err):
+		check.Status = compatUnsupported
+		check.Details = planRequirement
+	case isForbiddenError(err):
+		check.Status = compatNoAccess
+		check.Details = "requires organization owner access"
+	default:
+		check.Status = compatCheckFailure
+		check.Details = redactSecrets(err.Error())
+	}
+	return check
+}
+
+// probeEnterpriseGraphQL checks that the enterprise organization query,
+// including the fields the organization filters use, is available
+func probeEnterpriseGraphQL(hostname, enterprise string) compatCheck {
+	check := compatCheck{Feature: "Enterprise organization listing", UsedBy: "--all-orgs, orgs list, organization filters"}
+	client, err := graphqlClient(hostname)
+	if err != nil {
+		check.Status = compatCheckFailure
+		check.Details = err.Error()
+		return check
+	}
+
+	query := `{
+		enterprise(slug: "` + enterprise + `") {
+			organizations(first: 1) {
+				nodes {
+					login
+					viewerCanAdminister
+					createdAt
+					repositories {
+						totalCount
+					}
+				}
+			}
+		}
+	}`
+	var result struct{}
+	err = client.Do(query, nil, &result)
+	message := ""
+	if err != nil {
+		message = strings.ToLower(err.Error())
+	}
+	switch {
+	case err == nil:
+		check.Status = compatSupported
+		check.Details = "enterprise " + enterprise
+	case strings.Contains(message, "doesn't exist on type") || strings.Contains(message, "undefinedfield"):
+		check.Status = compatUnsupported
+		check.Details = redactSecrets(err.Error())
+	case strings.Contains(message, "could not resolve"):
+		check.Status = compatCheckFailure
+		check.Details = "enterprise " + enterprise + " not found"
+	case strings.Contains(message, "forbidden") || strings.Contains(message, "scope"):
+		check.Status = compatNoAccess
+		check.Details = redactSecrets(err.Error())
+	default:
+		check.Status = compatCheckFailure
+		check.Details = redactSecrets(err.Error())
+	}
+	return check
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Summary categories for 422 validation failures
+const (
+	categoryNameTaken         = "Role name already exists"
+	categoryRoleLimit         = "Custom role limit reached"
+	categoryInvalidPermission = "Invalid permission"
+	categoryValidation        = "Validation failed"
+)
+
+// Summary categories for other failures, classified from the error message
+const (
+	categoryAuth        = "Authentication failed"
+	categoryForbidden   = "Permission denied"
+	categoryRateLimited = "Rate limited"
+	categoryNotFound    = "Not found"
+	categoryServer      = "Server error"
+	categoryNetwork     = "Network error"
+	categoryOther       = "Other errors"
+)
+
+// categoryAtQuota is the warning category for organizations skipped with
+// --skip-at-quota because they cannot hold another custom role
+const categoryAtQuota = "Custom role quota reached"
+
+// defaultRoleQuota is the number of custom repository roles an organization
+// can hold on GitHub Enterprise Cloud
+const defaultRoleQuota = 20
+
+// categoryPlanUnsupported is the warning category for organizations whose
+// plan does not include custom repository roles
+const categoryPlanUnsupported = "Plan unsupported"
+
+// planRequirement is shown when an organization's plan lacks custom roles
+const planRequirement = "custom repository roles require GitHub Enterprise Cloud or GitHub Enterprise Server"
+
+// validationFailure extracts the field-level errors from a 422 response and
+// classifies them. ok is false for any other error.
+func validationFailure(err error) (category, message string, ok bool) {
+	var httpErr *api.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusUnprocessableEntity {
+		return "", "", false
+	}
+
+	var details []string
+	var codes []string
+	for _, item := range httpErr.Errors {
+		detail := item.Message
+		if detail == "" {
+			detail = strings.ReplaceAll(item.Code, "_", " ")
+		}
+		if item.Field != "" && !strings.Contains(strings.ToLower(detail), strings.ToLower(item.Field)) {
+			detail = item.Field + ": " + detail
+		}
+		if detail != "" {
+			details = append(details, detail)
+		}
+		codes = append(codes, item.Code)
+	}
+
+	message, _, _ = strings.Cut(httpErr.Message, "\n")
+	if len(details) > 0 {
+		message = strings.Join(details, "; ")
+	}
+
+	text := strings.ToLower(message + " " + strings.Join(codes, " "))
+	switch {
+	case strings.Contains(text, "already_exists") || strings.Contains(text, "already exists") || strings.Contains(text, "already taken"):
+		category = categoryNameTaken
+	case strings.Contains(text, "too many") || strings.Contains(text, "limit") || strings.Contains(text, "maximum"):
+		category = categoryRoleLimit
+	case strings.Contains(text, "permission"):
+		category = categoryInvalidPermission
+	default:
+		category = categoryValidation
+	}
+	return category, message, true
+}
+
+// isPlanUnsupportedError reports whether err is a 403 saying custom
+// repository roles are not available to the organization, as opposed to a
+// missing permission or a 404
+func isPlanUnsupportedError(err error) bool {
+	var httpErr *api.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusForbidden {
+		return false
+	}
+	message := strings.ToLower(httpErr.Message)
+	for _, marker := range []string{"not available", "upgrade", "plan", "enterprise"} {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isForbiddenError reports whether err is a 403, such as a request that
+// needs organization owner access
+func isForbiddenError(err error) bool {
+	var httpErr *api.HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusForbidden
+}
+
+// serverErrorPattern matches the status of a 5xx API error message
+var serverErrorPattern = regexp.MustCompile(`http 5\d\d`)
+
+// classifyFailure returns the root cause category of a failure that was
+// recorded without one, based on its error message
+func classifyFailure(message string) string {
+	text := strings.ToLower(message)
+	switch {
+	case strings.Contains(text, "http 401") || strings.Contains(text, "bad credentials") || strings.Contains(text, "requires authentication"):
+		return categoryAuth
+	case strings.Contains(text, "rate limit") || strings.Contains(text, "http 429"):
+		return categoryRateLimited
+	case strings.Contains(text, "http 403"):
+		return categoryForbidden
+	case strings.Contains(text, "http 404"):
+		return categoryNotFound
+	case serverErrorPattern.MatchString(text):
+		return categoryServer
+	}
+	for _, marker := range []string{"timeout", "connection refused", "connection reset", "no such host", "eof", "tls", "network"} {
+		if strings.Contains(text, marker) {
+			return categoryNetwork
+		}
+	}
+	return categoryOther
+}
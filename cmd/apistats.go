@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiStats counts the API traffic of the process for the run summary
+type apiStats struct {
+	mu        sync.Mutex
+	requests  map[string]int
+	retries   int
+	throttled time.Duration
+}
+
+var apiUsage = &apiStats{requests: map[string]int{}}
+
+// apiStatsSnapshot is a copy of the API statistics at one point in time
+type apiStatsSnapshot struct {
+	Requests           map[string]int `json:"requests"`
+	Retries            int            `json:"retries"`
+	RateLimitedSeconds float64        `json:"rate_limited_seconds"`
+}
+
+func (s *apiStats) recordRequest(kind string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[kind]++
+}
+
+func (s *apiStats) recordRetry() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retries++
+}
+
+func (s *apiStats) recordThrottle(wait time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.throttled += wait
+}
+
+func (s *apiStats) Snapshot() apiStatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	requests := make(map[string]int, len(s.requests))
+	for kind, count := range s.requests {
+		requests[kind] = count
+	}
+	return apiStatsSnapshot{Requests: requests, Retries: s.retries, RateLimitedSeconds: s.throttled.Seconds()}
+}
+
+// Total returns the number of requests of every kind
+func (s apiStatsSnapshot) Total() int {
+	total := 0
+	for _, count := range s.Requests {
+		total += count
+	}
+	return total
+}
+
+// String describes the requests by kind, such as
+// "42 (GraphQL 2, REST GET 30, REST POST 10)"
+func (s apiStatsSnapshot) String() string {
+	kinds := make([]string, 0, len(s.Requests))
+	for kind := range s.Requests {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	parts := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		parts = append(parts, fmt.Sprintf("%s %d", kind, s.Requests[kind]))
+	}
+	if len(parts) == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%d (%s)", s.Total(), strings.Join(parts, ", "))
+}
+
+// RateLimited returns the time requests spent waiting for the rate limiter
+func (s apiStatsSnapshot) RateLimited() time.Duration {
+	return time.Duration(s.RateLimitedSeconds * float64(time.Second)).Round(100 * time.Millisecond)
+}
+
+// statsTransport counts every request sent to the API, including retries,
+// by kind: GraphQL or the REST method
+type statsTransport struct {
+	base http.RoundTripper
+}
+
+func (t statsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	kind := "REST " + req.Method
+	if strings.HasSuffix(req.URL.Path, "/graphql") {
+		kind = "GraphQL"
+	}
+	apiUsage.recordRequest(kind)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+// restAPIVersion is the REST API version requested from hosts that support
+// versioned requests
+const restAPIVersion = "2022-11-28"
+
+// minVersionedGHES is the first GitHub Enterprise Server release that
+// accepts the X-GitHub-Api-Version header. Older releases reject it, and
+// omitting it selects the same default version on every release.
+var minVersionedGHES = [2]int{3, 9}
+
+// ghesVersions records the installed version each GitHub Enterprise Server
+// host reports in its X-GitHub-Enterprise-Version response header
+var ghesVersions = struct {
+	sync.Mutex
+	byHost map[string]string
+}{byHost: map[string]string{}}
+
+// apiVersionTransport chooses the X-GitHub-Api-Version header of every REST
+// request for its host. GitHub.com and GHE.com always get restAPIVersion.
+// GitHub Enterprise Server only gets it once the installed version is known
+// to support it; until the first response reveals the version, the header is
+// omitted so the server's default applies.
+type apiVersionTransport struct {
+	base http.RoundTripper
+}
+
+func (t apiVersionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.HasSuffix(req.URL.Path, "/graphql") {
+		version := apiVersionFor(req.URL.Hostname())
+		if current := req.Header.Get("X-GitHub-Api-Version"); current != version {
+			req = req.Clone(req.Context())
+			if version == "" {
+				req.Header.Del("X-GitHub-Api-Version")
+			} else {
+				req.Header.Set("X-GitHub-Api-Version", version)
+			}
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		if version := resp.Header.Get("X-GitHub-Enterprise-Version"); version != "" {
+			ghesVersions.Lock()
+			ghesVersions.byHost[normalizeHostname(req.URL.Hostname())] = version
+			ghesVersions.Unlock()
+		}
+	}
+	return resp, err
+}
+
+// apiVersionFor returns the API version header value for requests to host,
+// or an empty string to omit the header
+func apiVersionFor(host string) string {
+	hostname := normalizeHostname(host)
+	if !auth.IsEnterprise(hostname) || auth.IsTenancy(hostname) {
+		return restAPIVersion
+	}
+	if supportsVersionedAPI(ghesVersion(hostname)) {
+		return restAPIVersion
+	}
+	return ""
+}
+
+// ghesVersion returns the installed version of a GitHub Enterprise Server
+// host, or an empty string if no response from it has been seen yet
+func ghesVersion(hostname string) string {
+	ghesVersions.Lock()
+	defer ghesVersions.Unlock()
+	return ghesVersions.byHost[normalizeHostname(hostname)]
+}
+
+// supportsVersionedAPI reports whether a GHES version such as 3.15.2 accepts
+// the X-GitHub-Api-Version header
+func supportsVersionedAPI(version string) bool {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return false
+	}
+	major, majorErr := strconv.Atoi(parts[0])
+	minor, minorErr := strconv.Atoi(parts[1])
+	if majorErr != nil || minorErr != nil {
+		return false
+	}
+	return major > minVersionedGHES[0] || (major == minVersionedGHES[0] && minor >= minVersionedGHES[1])
+}
@@ -0,0 +1,501 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+type assignmentRule struct {
+	Row         int
+	Org         string
+	Team        string
+	RepoPattern string
+	Role        string
+}
+
+type teamRepoAssignment struct {
+	Org  string
+	Team string
+	Repo string
+	Role string
+}
+
+type repository struct {
+	Name   string   `json:"name"`
+	Topics []string `json:"topics"`
+}
+
+// searchResultLimit is the most results the search API returns for one
+// query, however many repositories match
+const searchResultLimit = 1000
+
+// topicPattern matches a valid repository topic: lowercase letters, numbers,
+// and hyphens, up to 50 characters
+var topicPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}$`)
+
+var assignCmd = &cobra.Command{
+	Use:         "assign",
+	Short:       "Assign custom repository roles to teams on repositories",
+	RunE:        runAssign,
+	Annotations: mutatingCommand,
+}
+
+func init() {
+	// Assign command flags
+	assignCmd.Flags().StringVarP(&opts.mappingPath, "mapping", "m", "", "CSV file with org,team,repo-or-pattern,role-name rows")
+	assignCmd.Flags().StringVarP(&opts.roleName, "role-name", "n", "", "Custom role name to assign (with --repo-topic)")
+	assignCmd.Flags().StringVarP(&opts.team, "team", "t", "", "Team slug to assign the role to (with --repo-topic)")
+	assignCmd.Flags().StringVar(&opts.repoTopic, "repo-topic", "", "Assign the role on every repository with this topic in the target organizations")
+	assignCmd.MarkFlagsMutuallyExclusive("mapping", "repo-topic")
+}
+
+func runAssign(_ *cobra.Command, _ []string) error {
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	// Topics are lowercase on GitHub, so --repo-topic is matched the same way
+	opts.repoTopic = strings.ToLower(strings.TrimSpace(opts.repoTopic))
+	if opts.mappingPath == "" && opts.repoTopic == "" {
+		mode, modeErr := promptSelect("Select assignment source", []string{"Mapping CSV file", "Repositories with a topic"}, "")
+		if modeErr != nil {
+			return modeErr
+		}
+		switch mode {
+		case "Mapping CSV file":
+			opts.mappingPath, err = promptText("Path to mapping CSV file")
+			if err != nil {
+				return err
+			}
+			opts.mappingPath = strings.TrimSpace(opts.mappingPath)
+			if opts.mappingPath == "" {
+				return errors.New("mapping file path is required")
+			}
+		case "Repositories with a topic":
+			opts.repoTopic, err = promptText("Repository topic")
+			if err != nil {
+				return err
+			}
+			opts.repoTopic = strings.ToLower(strings.TrimSpace(opts.repoTopic))
+			if opts.repoTopic == "" {
+				return errors.New("repository topic is required")
+			}
+		default:
+			return errors.New("invalid assignment source")
+		}
+	}
+
+	if opts.repoTopic != "" && !topicPattern.MatchString(opts.repoTopic) {
+		return fmt.Errorf("invalid repository topic %q: topics use lowercase letters, numbers, and hyphens", opts.repoTopic)
+	}
+
+	var assignments []teamRepoAssignment
+	if opts.mappingPath != "" {
+		assignments, err = resolveMappingAssignments()
+	} else {
+		assignments, err = resolveTopicAssignments()
+	}
+	if err != nil {
+		return err
+	}
+	if len(assignments) == 0 {
+		return errors.New("no repositories matched the assignment criteria")
+	}
+
+	// Display confirmation before assigning roles
+	pterm.Println()
+	pterm.DefaultSection.Println("Confirmation")
+	printAccount(opts.hostname)
+	if opts.mappingPath != "" {
+		pterm.Info.Printfln("Mapping File: %s", opts.mappingPath)
+	} else {
+		pterm.Info.Printfln("Role Name: %s", opts.roleName)
+		pterm.Info.Printfln("Team: %s", opts.team)
+		pterm.Info.Printfln("Repository Topic: %s", opts.repoTopic)
+	}
+	pterm.Info.Printfln("Team Repository Grants: %d", len(assignments))
+	checkRateLimitBudget(opts.hostname, len(assignments))
+	pterm.Println()
+
+	// Validate concurrency and delay bounds
+	if err := validatePacing(opts); err != nil {
+		return err
+	}
+
+	confirm, err := confirmRun("Begin role assignment?")
+	if err != nil {
+		return err
+	}
+	if !confirm {
+		pterm.Info.Println("Role assignment cancelled.")
+		return nil
+	}
+	pterm.Println()
+
+	savePromptDefaults(opts, "")
+
+	results := newRunResults(len(assignments))
+
+	progressBar, err := startProgressbar(len(assignments), "Assigning custom roles")
+	if err != nil {
+		return err
+	}
+	defer progressBar.Stop()
+
+	results.Track(progressBar)
+
+	processQueue(opts, untilStopped(queueTargets(assignments), results.Stopped()), func(assignment teamRepoAssignment) {
+		assignErr := assignTeamRepoRole(opts.hostname, assignment)
+		target := assignment.Org + "/" + assignment.Repo + " (" + assignment.Team + ")"
+		switch {
+		case assignErr != nil && isNotFoundError(assignErr):
+			results.Skipped(target, "Team or repository not found", "Team %s or repository %s/%s not found. Skipping.", assignment.Team, assignment.Org, assignment.Repo)
+		case assignErr != nil:
+			results.Failed(target, assignErr.Error(), "Failed to assign %s to team %s on %s/%s: %v", assignment.Role, assignment.Team, assignment.Org, assignment.Repo, assignErr)
+		default:
+			results.Succeeded(target, "Assigned "+assignment.Role, "Assigned %s to team %s on %s/%s", assignment.Role, assignment.Team, assignment.Org, assignment.Repo)
+		}
+	})
+
+	progressBar.Stop()
+
+	// Display summary
+	pterm.Println()
+	pterm.DefaultSection.Println("Summary")
+	results.printSummaryCounts("assigned")
+
+	// Display command for replication
+	cmd := buildAssignReplicationCommand(opts)
+	printReplicationTip("these changes", cmd)
+
+	// Publish the run summary (GitHub Actions step summary, tracking issue)
+	details := []summaryDetail{
+		{Label: "Team Repository Grants", Value: fmt.Sprintf("%d", len(assignments))},
+		{Label: "Replication Command", Value: "`" + cmd + "`"},
+	}
+	publishRunSummary(opts, "Custom role assignment", details, results)
+
+	return results.Err()
+}
+
+// resolveMappingAssignments loads the mapping file and expands it into grants
+func resolveMappingAssignments() ([]teamRepoAssignment, error) {
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, false); err != nil {
+		return nil, err
+	}
+
+	rules, err := loadAssignmentMapping(opts.mappingPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, errors.New("no assignments found in mapping file")
+	}
+	return expandAssignments(opts.hostname, rules)
+}
+
+// resolveTopicAssignments finds repositories with the requested topic in each
+// target organization and grants the role to the team on each of them
+func resolveTopicAssignments() ([]teamRepoAssignment, error) {
+	if err := selectTargets(); err != nil {
+		return nil, err
+	}
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, opts.allOrgs); err != nil {
+		return nil, err
+	}
+
+	if err := resolveEnterprise(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if opts.team == "" {
+		opts.team, err = promptText("Team slug")
+		if err != nil {
+			return nil, err
+		}
+	}
+	opts.team = strings.ToLower(strings.TrimSpace(opts.team))
+	if opts.team == "" {
+		return nil, errors.New("team slug is required")
+	}
+
+	if opts.roleName == "" {
+		opts.roleName, err = promptText("Custom role name")
+		if err != nil {
+			return nil, err
+		}
+	}
+	opts.roleName = strings.TrimSpace(opts.roleName)
+	if opts.roleName == "" {
+		return nil, errors.New("role name is required")
+	}
+
+	orgs, err := resolveOrganizations(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(orgs) == 0 {
+		return nil, errors.New("no organizations provided")
+	}
+
+	var assignments []teamRepoAssignment
+	for _, org := range orgs {
+		pterm.Info.Printfln("Searching %s for repositories with topic %s...", org, opts.repoTopic)
+		repos, err := searchRepositoriesByTopic(opts.hostname, org, opts.repoTopic)
+		if err != nil {
+			if isNotFoundError(err) {
+				pterm.Warning.Printfln("Organization %s not found. Skipping.", org)
+				continue
+			}
+			return nil, err
+		}
+		for _, repo := range repos {
+			assignments = append(assignments, teamRepoAssignment{Org: org, Team: opts.team, Repo: repo, Role: opts.roleName})
+		}
+	}
+	return assignments, nil
+}
+
+// loadAssignmentMapping reads org,team,repo-or-pattern,role-name rows from a CSV file
+func loadAssignmentMapping(path string) ([]assignmentRule, error) {
+	cleanPath := filepath.Clean(path)
+	file, err := os.Open(cleanPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := newCSVReader(file)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []assignmentRule
+	for i, record := range records {
+		row := i + 1
+		if isBlankRecord(record) {
+			continue
+		}
+		// Allow an optional header row
+		if row == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "org") {
+			continue
+		}
+		if len(record) != 4 {
+			return nil, fmt.Errorf("mapping row %d: expected 4 columns (org,team,repo-or-pattern,role-name), got %d", row, len(record))
+		}
+		rule := assignmentRule{
+			Row:         row,
+			Org:         normalizeOrg(record[0]),
+			Team:        strings.ToLower(strings.TrimSpace(record[1])),
+			RepoPattern: strings.TrimSpace(record[2]),
+			Role:        strings.TrimSpace(record[3]),
+		}
+		if rule.Org == "" || rule.Team == "" || rule.RepoPattern == "" || rule.Role == "" {
+			return nil, fmt.Errorf("mapping row %d: org, team, repository, and role name are all required", row)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// expandAssignments resolves repository patterns into individual team repository grants
+func expandAssignments(hostname string, rules []assignmentRule) ([]teamRepoAssignment, error) {
+	repoCache := map[string][]string{}
+	seen := map[teamRepoAssignment]bool{}
+	var assignments []teamRepoAssignment
+
+	add := func(assignment teamRepoAssignment) {
+		if seen[assignment] {
+			return
+		}
+		seen[assignment] = true
+		assignments = append(assignments, assignment)
+	}
+
+	for _, rule := range rules {
+		if !isRepoPattern(rule.RepoPattern) {
+			add(teamRepoAssignment{Org: rule.Org, Team: rule.Team, Repo: rule.RepoPattern, Role: rule.Role})
+			continue
+		}
+
+		repos, cached := repoCache[rule.Org]
+		if !cached {
+			pterm.Info.Printfln("Listing repositories in %s...", rule.Org)
+			var err error
+			repos, err = listOrgRepositories(hostname, rule.Org)
+			if err != nil {
+				return nil, fmt.Errorf("mapping row %d: %w", rule.Row, err)
+			}
+			repoCache[rule.Org] = repos
+		}
+
+		matched := 0
+		for _, repo := range repos {
+			ok, err := path.Match(strings.ToLower(rule.RepoPattern), strings.ToLower(repo))
+			if err != nil {
+				return nil, fmt.Errorf("mapping row %d: invalid repository pattern %q: %w", rule.Row, rule.RepoPattern, err)
+			}
+			if ok {
+				add(teamRepoAssignment{Org: rule.Org, Team: rule.Team, Repo: repo, Role: rule.Role})
+				matched++
+			}
+		}
+		if matched == 0 {
+			pterm.Warning.Printfln("Mapping row %d: pattern %s matched no repositories in %s", rule.Row, rule.RepoPattern, rule.Org)
+		}
+	}
+	return assignments, nil
+}
+
+func isRepoPattern(value string) bool {
+	return strings.ContainsAny(value, "*?[")
+}
+
+func listOrgRepositories(hostname, org string) ([]string, error) {
+	response, stderr, err := ghAPI(hostname, "--paginate", "orgs/"+org+"/repos?per_page=100")
+	if err != nil {
+		return nil, fmt.Errorf("repository lookup failed: %w (%s)", err, stderr.String())
+	}
+
+	page, err := decodeArrayPages[repository](response.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	repos := make([]string, 0, len(page))
+	for _, repo := range page {
+		repos = append(repos, repo.Name)
+	}
+	return repos, nil
+}
+
+// searchRepositoriesByTopic returns the repositories in org with topic. The
+// search API stops at 1,000 results and may time out with partial results,
+// so in either case every repository of the organization is listed and
+// filtered by topic instead.
+func searchRepositoriesByTopic(hostname, org, topic string) ([]string, error) {
+	response, stderr, err := ghAPI(hostname,
+		"--paginate",
+		"-X", "GET",
+		"search/repositories",
+		"-f", "q=org:"+org+" topic:"+topic,
+		"-f", "per_page=100",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository search failed: %w (%s)", err, stderr.String())
+	}
+
+	// Paginated output contains one search result object per page
+	var repos []string
+	total, incomplete := 0, false
+	decoder := json.NewDecoder(&response)
+	for {
+		var page struct {
+			TotalCount        int          `json:"total_count"`
+			IncompleteResults bool         `json:"incomplete_results"`
+			Items             []repository `json:"items"`
+		}
+		if err := decoder.Decode(&page); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		total = max(total, page.TotalCount)
+		incomplete = incomplete || page.IncompleteResults
+		for _, repo := range page.Items {
+			repos = append(repos, repo.Name)
+		}
+	}
+	repos = uniqueStrings(repos)
+
+	switch {
+	case total > searchResultLimit:
+		pterm.Info.Printfln("%d repositories in %s have topic %s, more than search returns; listing every repository instead...", total, org, topic)
+	case incomplete || len(repos) < total:
+		pterm.Info.Printfln("Search returned %d of %d repositories with topic %s in %s; listing every repository instead...", len(repos), total, topic, org)
+	default:
+		return repos, nil
+	}
+	return listRepositoriesWithTopic(hostname, org, topic)
+}
+
+// listRepositoriesWithTopic lists every repository in org and keeps those
+// with topic
+func listRepositoriesWithTopic(hostname, org, topic string) ([]string, error) {
+	response, stderr, err := ghAPI(hostname, "--paginate", "orgs/"+org+"/repos?per_page=100")
+	if err != nil {
+		return nil, fmt.Errorf("repository lookup failed: %w (%s)", err, stderr.String())
+	}
+
+	page, err := decodeArrayPages[repository](response.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []string
+	for _, repo := range page {
+		if slices.Contains(repo.Topics, topic) {
+			repos = append(repos, repo.Name)
+		}
+	}
+	return repos, nil
+}
+
+func assignTeamRepoRole(hostname string, assignment teamRepoAssignment) error {
+	_, stderr, err := ghAPI(hostname,
+		"-X", "PUT",
+		"orgs/"+assignment.Org+"/teams/"+assignment.Team+"/repos/"+assignment.Org+"/"+assignment.Repo,
+		"-f", "permission="+assignment.Role,
+	)
+	if err != nil {
+		return fmt.Errorf("assign role failed: %w (%s)", err, stderr.String())
+	}
+	return nil
+}
+
+func buildAssignReplicationCommand(opts options) string {
+	cmd := "gh custom-roles assign"
+
+	if opts.hostname != "" {
+		cmd += " --hostname " + shellQuote(opts.hostname)
+	}
+	if opts.mappingPath != "" {
+		cmd += " --mapping " + shellQuote(opts.mappingPath)
+	} else {
+		cmd += targetFlags(opts)
+		cmd += " --team " + shellQuote(opts.team)
+		cmd += " --repo-topic " + shellQuote(opts.repoTopic)
+		cmd += " --role-name " + shellQuote(opts.roleName)
+	}
+	cmd += pacingFlags(opts)
+
+	return cmd
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// Role kinds reported by the assignments command
+const (
+	roleKindBase   = "base"
+	roleKindCustom = "custom"
+)
+
+// baseRoleNames are the built-in repository roles; any other role name is a
+// custom role
+var baseRoleNames = map[string]bool{"read": true, "triage": true, "write": true, "maintain": true, "admin": true}
+
+var assignmentsCmd = &cobra.Command{
+	Use:   "assignments",
+	Short: "List the teams and collaborators on repositories and whether they hold custom or base roles",
+	RunE:  runAssignments,
+}
+
+func init() {
+	// Assignments command flags
+	assignmentsCmd.Flags().StringSliceVar(&opts.repos, "repo", nil, "Repository to review as org/repo (repeatable)")
+	assignmentsCmd.Flags().BoolVar(&opts.allRepos, "all-repos", false, "Review every repository in the target organizations")
+	assignmentsCmd.Flags().StringVar(&opts.assignmentsFormat, "format", formatTable, "Output format: table, csv, json, yaml, or markdown")
+	assignmentsCmd.Flags().StringVar(&opts.outputPath, "output", "", "Write csv, json, yaml, or markdown output to this file instead of stdout")
+	assignmentsCmd.MarkFlagsMutuallyExclusive("repo", "all-repos")
+}
+
+func runAssignments(_ *cobra.Command, _ []string) error {
+	if err := validateOutputFormat(opts); err != nil {
+		return err
+	}
+
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.repos) == 0 && !opts.allRepos {
+		repo, err := promptText("Repository to review (org/repo)")
+		if err != nil {
+			return err
+		}
+		opts.repos = []string{repo}
+	}
+
+	var grants []repoGrant
+	if opts.allRepos {
+		if err := selectTargets(); err != nil {
+			return err
+		}
+
+		// Validate GitHub environment (GHES version and OAuth scopes)
+		if err := validateGitHubEnvironment(opts.hostname, opts.allOrgs); err != nil {
+			return err
+		}
+
+		if err := resolveEnterprise(); err != nil {
+			return err
+		}
+
+		orgs, err := resolveOrganizations(opts)
+		if err != nil {
+			return err
+		}
+		if len(orgs) == 0 {
+			return errors.New("no organizations provided")
+		}
+
+		// Validate concurrency and delay bounds
+		if err := validatePacing(opts); err != nil {
+			return err
+		}
+
+		grants, err = scanRepoGrants(opts, orgs, func(repoGrant) bool { return true })
+		if err != nil {
+			return err
+		}
+	} else {
+		// Validate GitHub environment (GHES version and OAuth scopes)
+		if err := validateGitHubEnvironment(opts.hostname, false); err != nil {
+			return err
+		}
+
+		// Validate concurrency and delay bounds
+		if err := validatePacing(opts); err != nil {
+			return err
+		}
+
+		grants, err = listRepositoryGrants(opts, opts.repos)
+		if err != nil {
+			return err
+		}
+	}
+
+	customCount := 0
+	for _, grant := range grants {
+		if roleKind(grant.Role) == roleKindCustom {
+			customCount++
+		}
+	}
+
+	if opts.outputFormat == formatTable {
+		pterm.Println()
+		pterm.DefaultSection.Println("Repository assignments")
+		if err := printAssignmentsTable(grants); err != nil {
+			return err
+		}
+	} else {
+		if err := exportAssignments(opts, grants); err != nil {
+			return fmt.Errorf("failed to export assignments: %w", err)
+		}
+		if opts.outputPath != "" {
+			pterm.Success.Printfln("Wrote %d assignments to %s", len(grants), opts.outputPath)
+		}
+	}
+
+	// Display summary
+	pterm.Println()
+	pterm.DefaultSection.Println("Summary")
+	pterm.Info.Printfln("✓ Assignments found: %d", len(grants))
+	pterm.Info.Printfln("Custom role grants: %d", customCount)
+	pterm.Info.Printfln("Base role grants: %d", len(grants)-customCount)
+
+	// Display command for replication
+	printReplicationTip("this review", buildAssignmentsReplicationCommand(opts))
+
+	return nil
+}
+
+// listRepositoryGrants lists the grants on explicitly named repositories
+func listRepositoryGrants(opts options, repos []string) ([]repoGrant, error) {
+	type orgRepo struct {
+		Org  string
+		Repo string
+	}
+
+	var targets []orgRepo
+	for _, value := range repos {
+		org, repo, ok := strings.Cut(strings.TrimSpace(value), "/")
+		if !ok || org == "" || repo == "" {
+			return nil, fmt.Errorf("invalid repository %q: expected org/repo", value)
+		}
+		targets = append(targets, orgRepo{Org: normalizeOrg(org), Repo: repo})
+	}
+
+	var grants []repoGrant
+	var scanErr error
+	var mu sync.Mutex
+
+	processTargets(opts, targets, func(target orgRepo) {
+		found, err := listRepoGrants(opts.hostname, target.Org, target.Repo)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil && scanErr == nil {
+			scanErr = fmt.Errorf("failed to read %s/%s: %w", target.Org, target.Repo, err)
+		}
+		grants = append(grants, found...)
+	})
+	if scanErr != nil {
+		return nil, scanErr
+	}
+
+	sort.SliceStable(grants, func(i, j int) bool {
+		a, b := grants[i], grants[j]
+		if a.Org != b.Org {
+			return a.Org < b.Org
+		}
+		return a.Repo < b.Repo
+	})
+	return grants, nil
+}
+
+func printAssignmentsTable(grants []repoGrant) error {
+	if len(grants) == 0 {
+		pterm.Info.Println("No teams or direct collaborators found.")
+		return nil
+	}
+	data := pterm.TableData{{"Organization", "Repository", "Type", "Grantee", "Role", "Kind", "Source"}}
+	for _, grant := range grants {
+		record := newAssignmentRecord(grant)
+		data = append(data, []string{record.Org, record.Repo, record.GranteeType, record.Grantee, record.Role, record.RoleKind, record.Source})
+	}
+	return pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+}
+
+// roleKind reports whether a role is a built-in base role or a custom role
+func roleKind(role string) string {
+	if baseRoleNames[role] {
+		return roleKindBase
+	}
+	return roleKindCustom
+}
+
+func buildAssignmentsReplicationCommand(opts options) string {
+	cmd := "gh custom-roles assignments"
+
+	if opts.hostname != "" {
+		cmd += " --hostname " + shellQuote(opts.hostname)
+	}
+	if opts.allRepos {
+		cmd += " --all-repos"
+		cmd += targetFlags(opts)
+	} else {
+		for _, repo := range opts.repos {
+			cmd += " --repo " + shellQuote(repo)
+		}
+	}
+	cmd += formatFlags(opts)
+	cmd += pacingFlags(opts)
+
+	return cmd
+}
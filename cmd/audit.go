@@ -0,0 +1,377 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// defaultExpiryWindow is how far ahead audit looks for review dates
+const defaultExpiryWindow = "30d"
+
+// expiringRole is a role definition whose expiry or review date is past or
+// within the audit window, with the organizations that define the role
+type expiringRole struct {
+	Definition roleFile
+	// Annotation names the date that is due: expires or review_date
+	Annotation string
+	Due        time.Time
+	Orgs       []string
+}
+
+// Audit statuses in json and yaml output
+const (
+	auditStatusExpired  = "expired"
+	auditStatusExpiring = "expiring"
+)
+
+// auditRecord is one due role in json and yaml output
+type auditRecord struct {
+	// Host is set in multi-host runs
+	Host       string `json:"host,omitempty" yaml:"host,omitempty"`
+	Role       string `json:"role" yaml:"role"`
+	Owner      string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Annotation string `json:"annotation" yaml:"annotation"`
+	Date       string `json:"date" yaml:"date"`
+	Status     string `json:"status" yaml:"status"`
+	// Days until the date, negative once it has passed
+	Days int      `json:"days" yaml:"days"`
+	Orgs []string `json:"orgs" yaml:"orgs"`
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "List roles from definition files that are past or nearing their expiry or review date, and where they are defined",
+	Args:  cobra.NoArgs,
+	RunE:  runAudit,
+}
+
+func init() {
+	// Audit command flags
+	auditCmd.Flags().StringArrayVarP(&opts.manifestPaths, "from-file", "f", nil, "Role definition file, or directory of .yml, .yaml, and .json files, to audit (repeatable)")
+	auditCmd.Flags().StringVar(&opts.expiringWithin, "expiring-within", defaultExpiryWindow, "Report roles whose expires or review_date annotation falls within this window, in days (30d) or as a duration (72h)")
+	auditCmd.Flags().StringVar(&opts.auditFormat, "format", formatTable, "Output format: table, json, or yaml")
+	auditCmd.Flags().StringVar(&opts.outputPath, "output", "", "Write json or yaml output to this file instead of stdout")
+	_ = auditCmd.MarkFlagRequired("from-file")
+}
+
+func runAudit(_ *cobra.Command, _ []string) error {
+	if err := validateStructuredFormat(opts); err != nil {
+		return err
+	}
+	window, err := parseExpiryWindow(opts.expiringWithin)
+	if err != nil {
+		return err
+	}
+	definitions, err := loadRoleFiles(opts.manifestPaths)
+	if err != nil {
+		return err
+	}
+
+	// Dates are compared by calendar day, so a role expiring today is due
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	due := dueRoles(definitions, today.Add(window))
+	if len(due) == 0 {
+		if opts.outputFormat != formatTable {
+			if err := exportStructured(opts, []auditRecord{}); err != nil {
+				return fmt.Errorf("failed to write audit: %w", err)
+			}
+		}
+		pterm.Success.Printfln("No role expires or is due for review within %s (%s checked)", opts.expiringWithin, pluralize(len(definitions), "definition", "definitions"))
+		return nil
+	}
+
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	if err := selectTargets(); err != nil {
+		return err
+	}
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, opts.allOrgs); err != nil {
+		return err
+	}
+
+	if err := resolveEnterprise(); err != nil {
+		return err
+	}
+
+	orgs, err := resolveOrganizations(opts)
+	if err != nil {
+		return err
+	}
+	if len(orgs) == 0 {
+		return errors.New("no organizations provided")
+	}
+
+	// Validate concurrency and delay bounds
+	if err := validatePacing(opts); err != nil {
+		return err
+	}
+
+	inventory, err := collectCustomRoles(opts, orgs)
+	if err != nil {
+		return err
+	}
+	for i := range due {
+		for _, role := range inventory.Roles {
+			if sameRoleName(role.Role.Name, due[i].Definition.Name) {
+				due[i].Orgs = append(due[i].Orgs, role.Org)
+			}
+		}
+	}
+
+	var expired, expiring int
+	if opts.outputFormat == formatTable {
+		expired, expiring, err = printExpiringRoles(due, today)
+		if err != nil {
+			return err
+		}
+	} else {
+		records := auditRecords(due, today)
+		for _, record := range records {
+			if record.Status == auditStatusExpired {
+				expired++
+			} else {
+				expiring++
+			}
+		}
+		if err := exportStructured(opts, records); err != nil {
+			return fmt.Errorf("failed to write audit: %w", err)
+		}
+	}
+
+	// Display summary
+	pterm.Println()
+	pterm.DefaultSection.Println("Summary")
+	pterm.Info.Printfln("Role definitions audited: %d", len(definitions))
+	if expired > 0 {
+		pterm.Error.Printfln("✗ Past their expiry or review date: %d", expired)
+	}
+	if expiring > 0 {
+		pterm.Warning.Printfln("⚠ Due within %s: %d", opts.expiringWithin, expiring)
+	}
+	if expired+expiring == 0 {
+		pterm.Info.Println("Due roles defined in the target organizations: 0")
+	}
+	if inventory.ErrorCount > 0 {
+		pterm.Error.Printfln("✗ Errors: %d", inventory.ErrorCount)
+	}
+
+	// Display command for replication
+	printReplicationTip("this audit", buildAuditReplicationCommand(opts))
+
+	if inventory.ErrorCount > 0 {
+		return fmt.Errorf("completed with %d errors", inventory.ErrorCount)
+	}
+	if expired+expiring > 0 {
+		return fmt.Errorf("%d roles need recertification", expired+expiring)
+	}
+	return nil
+}
+
+// parseExpiryWindow converts --expiring-within into a duration. Days are
+// given as 30d; anything else is parsed as a Go duration such as 72h.
+func parseExpiryWindow(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		count, err := strconv.Atoi(days)
+		if err != nil || count < 0 {
+			return 0, fmt.Errorf("invalid --expiring-within %q: expected a number of days such as 30d", value)
+		}
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+	window, err := time.ParseDuration(value)
+	if err != nil || window < 0 {
+		return 0, fmt.Errorf("invalid --expiring-within %q: expected a number of days such as 30d or a duration such as 72h", value)
+	}
+	return window, nil
+}
+
+// loadRoleFiles reads role definitions from files and directories. A
+// directory contributes every .yml, .yaml, and .json file directly inside it,
+// in name order, and "-" reads one definition from stdin.
+func loadRoleFiles(paths []string) ([]roleFile, error) {
+	var files []string
+	for _, path := range paths {
+		if path == "-" {
+			files = append(files, path)
+			continue
+		}
+		info, err := os.Stat(filepath.Clean(path))
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		var found []string
+		for _, entry := range entries {
+			switch strings.ToLower(filepath.Ext(entry.Name())) {
+			case ".yml", ".yaml", ".json":
+				if !entry.IsDir() {
+					found = append(found, filepath.Join(path, entry.Name()))
+				}
+			}
+		}
+		if len(found) == 0 {
+			return nil, fmt.Errorf("directory %s contains no role definition files", path)
+		}
+		files = append(files, found...)
+	}
+
+	definitions := make([]roleFile, 0, len(files))
+	for _, file := range files {
+		definition, err := loadRoleFile(file)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, definition)
+	}
+	return definitions, nil
+}
+
+// dueRoles returns the definitions whose expires or review_date annotation
+// is on or before cutoff, soonest first. When both are set, the earlier
+// date counts.
+func dueRoles(definitions []roleFile, cutoff time.Time) []expiringRole {
+	var due []expiringRole
+	for _, definition := range definitions {
+		var role *expiringRole
+		for _, annotation := range []struct{ name, value string }{
+			{"expires", definition.Annotations.Expires},
+			{"review_date", definition.Annotations.ReviewDate},
+		} {
+			// Dates were validated when the file was loaded
+			date, err := time.Parse(time.DateOnly, annotation.value)
+			if err != nil || date.After(cutoff) {
+				continue
+			}
+			if role == nil || date.Before(role.Due) {
+				role = &expiringRole{Definition: definition, Annotation: annotation.name, Due: date}
+			}
+		}
+		if role != nil {
+			due = append(due, *role)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].Due.Before(due[j].Due)
+	})
+	return due
+}
+
+// printExpiringRoles lists the due roles that are defined in at least one
+// organization and returns how many are past due and how many are nearing
+// their date. Due roles that no organization defines need no recertification
+// and are only counted.
+func printExpiringRoles(due []expiringRole, today time.Time) (int, int, error) {
+	pterm.Println()
+	pterm.DefaultSection.Println("Roles due for recertification")
+
+	var expired, expiring, undefined int
+	data := pterm.TableData{{"Role", "Owner", "Date", "Status", "Organizations"}}
+	for _, role := range due {
+		if len(role.Orgs) == 0 {
+			undefined++
+			continue
+		}
+		days := int(role.Due.Sub(today).Hours() / 24)
+		var status string
+		switch {
+		case days < 0:
+			expired++
+			status = fmt.Sprintf("%s %s ago", role.Annotation, pluralize(-days, "day", "days"))
+		case days == 0:
+			expiring++
+			status = role.Annotation + " today"
+		default:
+			expiring++
+			status = fmt.Sprintf("%s in %s", role.Annotation, pluralize(days, "day", "days"))
+		}
+		data = append(data, []string{
+			role.Definition.Name,
+			role.Definition.Annotations.Owner,
+			role.Due.Format(time.DateOnly),
+			status,
+			fmt.Sprintf("%d (%s)", len(role.Orgs), previewList(role.Orgs)),
+		})
+	}
+
+	if len(data) > 1 {
+		if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+			return 0, 0, err
+		}
+	} else {
+		pterm.Info.Println("None of the due roles are defined in the target organizations.")
+	}
+	if undefined > 0 {
+		pterm.Info.Printfln("%s due but not defined in any target organization", pluralize(undefined, "role is", "roles are"))
+	}
+	return expired, expiring, nil
+}
+
+// auditRecords converts the due roles that are defined in at least one
+// organization for json and yaml output
+func auditRecords(due []expiringRole, today time.Time) []auditRecord {
+	records := []auditRecord{}
+	for _, role := range due {
+		if len(role.Orgs) == 0 {
+			continue
+		}
+		days := int(role.Due.Sub(today).Hours() / 24)
+		status := auditStatusExpiring
+		if days < 0 {
+			status = auditStatusExpired
+		}
+		record := auditRecord{
+			Role:       role.Definition.Name,
+			Owner:      role.Definition.Annotations.Owner,
+			Annotation: role.Annotation,
+			Date:       role.Due.Format(time.DateOnly),
+			Status:     status,
+			Days:       days,
+			Orgs:       role.Orgs,
+		}
+		if hostColumns() {
+			record.Host = opts.hostname
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func buildAuditReplicationCommand(opts options) string {
+	cmd := "gh custom-roles audit"
+
+	if opts.hostname != "" {
+		cmd += " --hostname " + shellQuote(opts.hostname)
+	}
+	cmd += targetFlags(opts)
+	for _, path := range opts.manifestPaths {
+		cmd += " --from-file " + shellQuote(path)
+	}
+	if opts.expiringWithin != defaultExpiryWindow {
+		cmd += " --expiring-within " + shellQuote(opts.expiringWithin)
+	}
+	cmd += formatFlags(opts)
+	cmd += pacingFlags(opts)
+
+	return cmd
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+// auditLogAttempts and auditLogRetryInterval bound how long the verification
+// waits for role creation events, which reach the audit log after a delay
+const (
+	auditLogAttempts      = 6
+	auditLogRetryInterval = 10 * time.Second
+)
+
+// Outcomes of checking one organization against the audit log
+const (
+	auditVerified        = "verified"
+	auditMissing         = "event missing"
+	auditUnexpectedActor = "unexpected actor"
+)
+
+// auditEvent is the part of a role.create audit log event the verification
+// reads. The role name is reported as name or role depending on the host.
+type auditEvent struct {
+	Action    string `json:"action"`
+	Actor     string `json:"actor"`
+	Org       string `json:"org"`
+	Name      string `json:"name"`
+	Role      string `json:"role"`
+	Timestamp int64  `json:"@timestamp"`
+}
+
+// auditFinding is the verification outcome for one organization
+type auditFinding struct {
+	Org    string
+	Status string
+	Actors []string
+}
+
+// verifyAuditLog checks that every organization in created has a role.create
+// event for roleName since started, performed by expected, the account the
+// run used. With an enterprise, its audit log is searched once per attempt;
+// otherwise each organization's audit log is searched.
+func verifyAuditLog(opts options, roleName string, created []string, started time.Time, expected string) ([]auditFinding, error) {
+	spinner, err := startSpinner("Verifying role creations against the audit log...")
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []auditFinding
+	for attempt := 1; ; attempt++ {
+		events, err := searchRoleCreations(opts, created, started)
+		if err != nil {
+			spinner.Fail("Failed to read the audit log")
+			return nil, err
+		}
+		findings = matchAuditEvents(events, roleName, created, expected)
+
+		pending := 0
+		for _, finding := range findings {
+			if finding.Status == auditMissing {
+				pending++
+			}
+		}
+		if pending == 0 || attempt == auditLogAttempts {
+			break
+		}
+		// Events can take a short while to be indexed
+		time.Sleep(auditLogRetryInterval)
+	}
+	spinner.Success("Checked the audit log")
+	return findings, nil
+}
+
+// searchRoleCreations returns the role.create events since started from the
+// enterprise audit log, or from each organization's audit log
+func searchRoleCreations(opts options, orgs []string, started time.Time) ([]auditEvent, error) {
+	phrase := "action:role.create created:>=" + started.UTC().Format("2006-01-02")
+	endpoints := []string{"enterprises/" + opts.enterprise + "/audit-log"}
+	if opts.enterprise == "" {
+		endpoints = nil
+		for _, org := range orgs {
+			endpoints = append(endpoints, "orgs/"+org+"/audit-log")
+		}
+	}
+
+	var events []auditEvent
+	for _, endpoint := range endpoints {
+		response, stderr, err := ghAPI(opts.hostname, "--paginate", endpoint, "-f", "phrase="+phrase, "-f", "per_page=100")
+		if err != nil {
+			return nil, fmt.Errorf("audit log search failed: %w (%s)", err, stderr.String())
+		}
+		page, err := decodeArrayPages[auditEvent](response.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range page {
+			if event.Timestamp >= started.UnixMilli() {
+				events = append(events, event)
+			}
+		}
+	}
+	return events, nil
+}
+
+// matchAuditEvents pairs each created organization with its role.create
+// events for roleName. Events that name a different role are ignored.
+func matchAuditEvents(events []auditEvent, roleName string, created []string, expected string) []auditFinding {
+	actors := map[string][]string{}
+	for _, event := range events {
+		name := event.Name
+		if name == "" {
+			name = event.Role
+		}
+		if name != "" && !sameRoleName(name, roleName) {
+			continue
+		}
+		org := normalizeOrg(event.Org)
+		actors[org] = append(actors[org], event.Actor)
+	}
+
+	findings := make([]auditFinding, 0, len(created))
+	for _, org := range created {
+		finding := auditFinding{Org: org, Status: auditMissing}
+		for _, actor := range actors[org] {
+			if strings.EqualFold(actor, expected) {
+				finding.Status = auditVerified
+				finding.Actors = nil
+				break
+			}
+			finding.Status = auditUnexpectedActor
+			finding.Actors = append(finding.Actors, actor)
+		}
+		findings = append(findings, finding)
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].Org < findings[j].Org
+	})
+	return findings
+}
+
+// printAuditFindings lists the organizations whose creation could not be
+// verified and returns how many there are
+func printAuditFindings(findings []auditFinding, expected string) (int, error) {
+	pterm.Println()
+	pterm.DefaultSection.Println("Audit log verification")
+
+	data := pterm.TableData{{"Organization", "Problem", "Actors"}}
+	for _, finding := range findings {
+		if finding.Status != auditVerified {
+			data = append(data, []string{finding.Org, finding.Status, strings.Join(uniqueStrings(finding.Actors), ", ")})
+		}
+	}
+	verified := len(findings) - (len(data) - 1)
+	if len(data) == 1 {
+		pterm.Success.Printfln("All %d role creations have a matching audit log event by %s", verified, expected)
+		return 0, nil
+	}
+	pterm.Warning.Printfln("%d of %d role creations could not be verified (expected actor: %s)", len(data)-1, len(findings), expected)
+	return len(data) - 1, pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+}
+
+// auditLogVerification verifies the organizations a run created the role in
+// and returns the result for the run summary, along with the number of
+// organizations that could not be verified. A failed search is reported as a
+// warning since the run itself already finished.
+func auditLogVerification(opts options, roleName string, created []string, started time.Time) (summaryDetail, int) {
+	detail := summaryDetail{Label: "Audit Log Verification"}
+	if len(created) == 0 {
+		detail.Value = "no roles created"
+		return detail, 0
+	}
+
+	expected, err := currentAccount(opts.hostname)
+	var findings []auditFinding
+	if err == nil {
+		findings, err = verifyAuditLog(opts, roleName, created, started, expected)
+	}
+	if err != nil {
+		pterm.Warning.Printfln("Could not verify the run against the audit log: %v", err)
+		pterm.Info.Println("Reading the audit log needs the read:audit_log scope (gh auth refresh -s read:audit_log) and owner access")
+		detail.Value = "not verified: " + redactSecrets(err.Error())
+		return detail, 0
+	}
+
+	unverified, err := printAuditFindings(findings, expected)
+	if err != nil {
+		pterm.Warning.Printfln("Could not print the audit log findings: %v", err)
+	}
+	detail.Value = fmt.Sprintf("%d of %d verified", len(findings)-unverified, len(findings))
+	return detail, unverified
+}
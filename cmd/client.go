@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// apiHeaders are sent with every REST request. The API version header is
+// chosen per host by apiVersionTransport.
+var apiHeaders = map[string]string{
+	"Accept": "application/vnd.github+json",
+}
+
+// sharedTransport is used by every API client so all workers share one pool
+// of keep-alive connections (HTTP/2 where the host supports it) instead of
+// paying a process start and TLS handshake per request. Requests that fail
+// with a transient error are retried with --retries, each attempt passing
+// through the per-host rate limiter, and are retried after re-authentication
+// when the token stops working mid-run. Every request sent, retries included,
+// gets the API version header its host supports and is counted for the run
+// summary.
+var sharedTransport http.RoundTripper = retryTransport{base: rateLimitedTransport{base: reauthTransport{base: apiVersionTransport{base: statsTransport{base: newSharedTransport()}}}}}
+
+func newSharedTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ForceAttemptHTTP2 = true
+	transport.MaxIdleConns = 100
+	// Enough idle connections for the highest --concurrency plus pre-checks
+	transport.MaxIdleConnsPerHost = 32
+	return transport
+}
+
+var (
+	apiClientsMu   sync.Mutex
+	restClients    = map[string]*api.RESTClient{}
+	graphqlClients = map[string]*api.GraphQLClient{}
+)
+
+// restClient returns the shared REST client for a host
+func restClient(hostname string) (*api.RESTClient, error) {
+	apiClientsMu.Lock()
+	defer apiClientsMu.Unlock()
+
+	if client, ok := restClients[hostname]; ok {
+		return client, nil
+	}
+	// go-gh adds the token to the headers map, so each client gets its own
+	// copy and a refreshed token is not shadowed by the previous one
+	client, err := api.NewRESTClient(api.ClientOptions{
+		Host:      hostname,
+		AuthToken: accountToken(hostname),
+		Headers:   maps.Clone(apiHeaders),
+		Transport: sharedTransport,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize GitHub API client: %w", err)
+	}
+	restClients[hostname] = client
+	return client, nil
+}
+
+// graphqlClient returns the shared GraphQL client for a host
+func graphqlClient(hostname string) (*api.GraphQLClient, error) {
+	apiClientsMu.Lock()
+	defer apiClientsMu.Unlock()
+
+	if client, ok := graphqlClients[hostname]; ok {
+		return client, nil
+	}
+	client, err := api.NewGraphQLClient(api.ClientOptions{
+		Host:      hostname,
+		AuthToken: accountToken(hostname),
+		Transport: sharedTransport,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize GitHub GraphQL client: %w", err)
+	}
+	graphqlClients[hostname] = client
+	return client, nil
+}
+
+// resetClients drops the cached clients for a host so the next request picks
+// up a refreshed token
+func resetClients(hostname string) {
+	apiClientsMu.Lock()
+	defer apiClientsMu.Unlock()
+
+	delete(restClients, hostname)
+	delete(graphqlClients, hostname)
+}
+
+var nextPagePattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// ghAPI performs a REST request using gh api style arguments: an endpoint,
+// -X METHOD, -f key=value fields (key[]=value for arrays) and --paginate.
+// Fields become query parameters for GET requests and a JSON body otherwise.
+// Paginated responses are concatenated page after page, as gh api does. On
+// failure the API error message is returned in the second buffer.
+func ghAPI(hostname string, args ...string) (bytes.Buffer, bytes.Buffer, error) {
+	var stdout, stderr bytes.Buffer
+
+	method := ""
+	endpoint := ""
+	paginate := false
+	var fields [][2]string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-X":
+			i++
+			if i < len(args) {
+				method = args[i]
+			}
+		case "-f":
+			i++
+			if i < len(args) {
+				key, value, _ := strings.Cut(args[i], "=")
+				fields = append(fields, [2]string{key, value})
+			}
+		case "--paginate":
+			paginate = true
+		default:
+			endpoint = args[i]
+		}
+	}
+	if method == "" {
+		method = http.MethodGet
+		if len(fields) > 0 {
+			method = http.MethodPost
+		}
+	}
+
+	client, err := restClient(hostname)
+	if err != nil {
+		return stdout, stderr, err
+	}
+
+	var body io.Reader
+	if method == http.MethodGet {
+		endpoint = withQuery(endpoint, fields)
+	} else if len(fields) > 0 {
+		payload, err := json.Marshal(fieldsBody(fields))
+		if err != nil {
+			return stdout, stderr, err
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	for endpoint != "" {
+		resp, err := client.Request(method, endpoint, body)
+		if err != nil {
+			var httpErr *api.HTTPError
+			if errors.As(err, &httpErr) {
+				fmt.Fprintf(&stderr, "gh: %s (HTTP %d)", httpErr.Message, httpErr.StatusCode)
+			}
+			return stdout, stderr, err
+		}
+		_, err = io.Copy(&stdout, resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return stdout, stderr, err
+		}
+
+		endpoint = ""
+		if paginate {
+			if match := nextPagePattern.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+				endpoint = match[1]
+			}
+		}
+	}
+	return stdout, stderr, nil
+}
+
+// withQuery appends fields to an endpoint as query parameters
+func withQuery(endpoint string, fields [][2]string) string {
+	if len(fields) == 0 {
+		return endpoint
+	}
+	query := url.Values{}
+	for _, field := range fields {
+		query.Add(field[0], field[1])
+	}
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+	return endpoint + separator + query.Encode()
+}
+
+// fieldsBody builds a JSON request body from fields, collecting key[] fields
+// into arrays
+func fieldsBody(fields [][2]string) map[string]any {
+	payload := map[string]any{}
+	for _, field := range fields {
+		if key, ok := strings.CutSuffix(field[0], "[]"); ok {
+			values, _ := payload[key].([]string)
+			payload[key] = append(values, field[1])
+			continue
+		}
+		payload[field[0]] = field[1]
+	}
+	return payload
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// validatePacing validates the --concurrency and --delay flags
+func validatePacing(opts options) error {
+	if opts.concurrency < 1 || opts.concurrency > 20 {
+		return fmt.Errorf("concurrency must be between 1 and 20 (got %d)", opts.concurrency)
+	}
+	if opts.delay < 0 {
+		return fmt.Errorf("delay must be non-negative (got %d)", opts.delay)
+	}
+	return nil
+}
+
+// pacingFlags returns the replication command flags for non-default pacing
+// and failure handling. With a pacing profile, only the flags that override
+// the profile are repeated.
+func pacingFlags(opts options) string {
+	var flags string
+	if opts.paceProfile != "" {
+		flags += " --profile-pace " + opts.paceProfile
+	}
+	fromProfile := func(name string) bool {
+		return opts.paceProfile != "" && !explicitPacing[name]
+	}
+	if opts.delay > 0 && !fromProfile("delay") {
+		flags += fmt.Sprintf(" --delay %d", opts.delay)
+	}
+	if opts.concurrency > 1 && !fromProfile("concurrency") {
+		flags += fmt.Sprintf(" --concurrency %d", opts.concurrency)
+	}
+	if opts.requestRate != defaultRequestRate && !fromProfile("requests-per-second") {
+		flags += fmt.Sprintf(" --requests-per-second %g", opts.requestRate)
+	}
+	if opts.retries > 0 && !fromProfile("retries") {
+		flags += fmt.Sprintf(" --retries %d", opts.retries)
+	}
+	if opts.jitter > 0 && !fromProfile("jitter") {
+		flags += fmt.Sprintf(" --jitter %g", opts.jitter)
+	}
+	if opts.maxFailures != "" && !fromProfile("max-failures") {
+		flags += " --max-failures " + shellQuote(opts.maxFailures)
+	}
+	if opts.failFast {
+		flags += " --fail-fast"
+	}
+	if opts.warningsAsErrors {
+		flags += " --warnings-as-errors"
+	}
+	return flags
+}
+
+// failureLimitFlag names the flag that set the failure limit, for messages
+func failureLimitFlag(opts options) string {
+	if opts.failFast {
+		return "--fail-fast"
+	}
+	return "--max-failures " + opts.maxFailures
+}
+
+// parseFailureLimit converts --max-failures into the number of failures a run
+// across total targets may have before it is aborted. The value is either an
+// absolute count or a percentage of the targets, such as 5%. A negative limit
+// means failures never abort the run.
+func parseFailureLimit(value string, total int) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return -1, nil
+	}
+	if percent, ok := strings.CutSuffix(value, "%"); ok {
+		p, err := strconv.ParseFloat(percent, 64)
+		if err != nil || p <= 0 || p > 100 {
+			return 0, fmt.Errorf("invalid --max-failures %q: percentage must be greater than 0 and at most 100", value)
+		}
+		return int(float64(total) * p / 100), nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 1 {
+		return 0, fmt.Errorf("invalid --max-failures %q: expected a positive count or a percentage such as 5%%", value)
+	}
+	return count, nil
+}
+
+// processTargets calls fn for each target. When a delay is set, targets are
+// processed sequentially with the delay between them; otherwise up to
+// opts.concurrency targets are processed in parallel. With --jitter, each
+// target also starts after a random wait. fn must guard any shared state it
+// touches.
+func processTargets[T any](opts options, targets []T, fn func(T)) {
+	processQueue(opts, queueTargets(targets), fn)
+}
+
+// queueTargets sends targets on a channel for processQueue
+func queueTargets[T any](targets []T) <-chan T {
+	queue := make(chan T)
+	go func() {
+		defer close(queue)
+		for _, target := range targets {
+			queue <- target
+		}
+	}()
+	return queue
+}
+
+// untilStopped forwards targets from queue until stop is closed. Targets that
+// arrive after that are discarded, so workers only finish what is in flight
+// and the producer is never left blocked.
+func untilStopped[T any](queue <-chan T, stop <-chan struct{}) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for target := range queue {
+			select {
+			case <-stop:
+			default:
+				select {
+				case out <- target:
+					continue
+				case <-stop:
+				}
+			}
+			break
+		}
+		// Discard what is left so the producer can finish
+		for range queue {
+		}
+	}()
+	return out
+}
+
+// processQueue calls fn for each target received from queue until it is
+// closed, using the same pacing rules as processTargets. Work starts as soon
+// as the first target arrives, so producers can keep resolving targets while
+// earlier ones are processed.
+func processQueue[T any](opts options, queue <-chan T, fn func(T)) {
+	// If delay is set, use sequential processing with delays
+	if opts.delay > 0 {
+		first := true
+		for target := range queue {
+			// Add delay between requests (except before the first one)
+			if !first {
+				time.Sleep(time.Duration(opts.delay)*time.Second + jitterDelay(opts.jitter))
+			}
+			first = false
+			fn(target)
+		}
+		return
+	}
+
+	// Use concurrent processing with semaphore
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, opts.concurrency)
+
+	for target := range queue {
+		wg.Add(1)
+		semaphore <- struct{}{} // Acquire semaphore
+
+		go func(target T) {
+			defer wg.Done()
+			defer func() { <-semaphore }() // Release semaphore
+
+			time.Sleep(jitterDelay(opts.jitter))
+			fn(target)
+		}(target)
+	}
+
+	wg.Wait()
+}
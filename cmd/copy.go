@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+var copyPermissionsCmd = &cobra.Command{
+	Use:         "copy-permissions",
+	Short:       "Copy the permissions of one custom role into an existing role in each organization",
+	RunE:        runCopyPermissions,
+	Annotations: mutatingCommand,
+}
+
+func init() {
+	// Copy permissions command flags
+	copyPermissionsCmd.Flags().StringVar(&opts.sourceOrg, "from-org", "", "Organization of the role to copy from (default the --org target)")
+	copyPermissionsCmd.Flags().StringVar(&opts.fromRole, "from-role", "", "Custom role to copy permissions from")
+	copyPermissionsCmd.Flags().StringVar(&opts.toRole, "to-role", "", "Existing custom role to copy permissions into")
+	copyPermissionsCmd.Flags().BoolVar(&opts.mergePermissions, "merge", false, "Add the copied permissions to the role's current ones instead of replacing them")
+	copyPermissionsCmd.Flags().StringVar(&opts.confirmEnterprise, "confirm-enterprise", "", "Enterprise slug, retyped to approve copying permissions with --all-orgs")
+}
+
+func runCopyPermissions(_ *cobra.Command, _ []string) error {
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	if err := selectTargets(); err != nil {
+		return err
+	}
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, opts.allOrgs); err != nil {
+		return err
+	}
+
+	if err := resolveEnterprise(); err != nil {
+		return err
+	}
+
+	orgs, err := resolveOrganizations(opts)
+	if err != nil {
+		return err
+	}
+	if len(orgs) == 0 {
+		return errors.New("no organizations provided")
+	}
+
+	if opts.sourceOrg == "" {
+		if opts.org != "" {
+			opts.sourceOrg = opts.org
+		} else {
+			opts.sourceOrg, err = promptText("Organization of the role to copy from")
+			if err != nil {
+				return err
+			}
+		}
+	}
+	opts.sourceOrg = normalizeOrg(opts.sourceOrg)
+	if opts.sourceOrg == "" {
+		return errors.New("source organization is required")
+	}
+
+	source, err := resolveSourceRole(opts.hostname, opts.sourceOrg, opts.fromRole)
+	if err != nil {
+		return err
+	}
+	opts.fromRole = source.Name
+
+	if opts.toRole == "" {
+		opts.toRole, err = promptText("Custom role to copy permissions into")
+		if err != nil {
+			return err
+		}
+	}
+	opts.toRole = strings.TrimSpace(opts.toRole)
+	if opts.toRole == "" {
+		return errors.New("destination role name is required")
+	}
+	if sameRoleName(opts.fromRole, opts.toRole) && len(orgs) == 1 && orgs[0] == opts.sourceOrg {
+		return errors.New("the source and destination are the same role")
+	}
+
+	// Validate concurrency and delay bounds
+	if err := validatePacing(opts); err != nil {
+		return err
+	}
+
+	mode := "Replace"
+	if opts.mergePermissions {
+		mode = "Merge"
+	}
+
+	// Display confirmation before copying permissions
+	pterm.Println()
+	pterm.DefaultSection.Println("Confirmation")
+	printAccount(opts.hostname)
+	pterm.Info.Printfln("Source Role: %s (%s)", source.Name, opts.sourceOrg)
+	pterm.Info.Printfln("Permissions: %s", strings.Join(source.Permissions, ", "))
+	pterm.Info.Printfln("Destination Role: %s", opts.toRole)
+	pterm.Info.Printfln("Mode: %s", mode)
+	pterm.Info.Printfln("Target Organizations: %d", len(orgs))
+
+	// Each organization needs a role lookup and an update request
+	checkRateLimitBudget(opts.hostname, len(orgs)*2)
+	pterm.Println()
+
+	confirm, err := confirmRun("Begin copying permissions?")
+	if err != nil {
+		return err
+	}
+	if !confirm {
+		pterm.Info.Println("Permission copy cancelled.")
+		return nil
+	}
+	if err := confirmEnterprise(opts, "copy permissions into "+opts.toRole); err != nil {
+		return err
+	}
+	pterm.Println()
+
+	results := newRunResults(len(orgs))
+
+	progressBar, err := startProgressbar(len(orgs), "Copying permissions")
+	if err != nil {
+		return err
+	}
+	defer progressBar.Stop()
+
+	results.Track(progressBar)
+
+	processQueue(opts, untilStopped(queueTargets(orgs), results.Stopped()), func(org string) {
+		roles, listErr := listCustomRoles(opts.hostname, org)
+		var destination customRole
+		found := false
+		for _, role := range roles {
+			if sameRoleName(role.Name, opts.toRole) {
+				destination, found = role, true
+				break
+			}
+		}
+
+		var permissions []string
+		if opts.mergePermissions {
+			permissions = uniqueStrings(append(append([]string(nil), destination.Permissions...), source.Permissions...))
+		} else {
+			permissions = source.Permissions
+		}
+		changes := roleChanges(destination, destination.Name, destination.Description, destination.BaseRole, permissions)
+		increase := blockedPrivilegeIncrease(destination, destination.BaseRole, permissions)
+
+		switch {
+		case listErr != nil && isNotFoundError(listErr):
+			results.Skipped(org, "Organization not found", "Organization %s not found. Skipping.", org)
+		case isPlanUnsupportedError(listErr):
+			results.SkippedAs(categoryPlanUnsupported, org, "Custom roles not available on this plan", "Organization %s cannot use custom roles: %s. Skipping.", org, planRequirement)
+		case listErr != nil:
+			results.Failed(org, listErr.Error(), "Failed to read custom roles in %s: %v", org, listErr)
+		case !found:
+			results.Skipped(org, "Role not found", "Organization %s has no role named %s. Skipping.", org, opts.toRole)
+		case excludedByManagedOnly(destination):
+			results.SkippedAs(categoryUnmanaged, org, "Role not managed by gh-custom-roles", "Role %s in %s is not managed by gh-custom-roles. Skipping (--managed-only).", destination.Name, org)
+		case len(changes) == 0:
+			results.Skipped(org, "Permissions already match", "Role %s in %s already has these permissions. Skipping.", destination.Name, org)
+		case increase != "":
+			results.FailedAs(categoryPrivilegeIncrease, org, "Privilege increase blocked: "+increase, "Not updating %s in %s: %s (pass --allow-privilege-increase to apply it)", destination.Name, org, increase)
+		default:
+			updateErr := updateCustomRole(opts.hostname, org, destination.ID, changes, permissions)
+			category, validationMessage, invalid := validationFailure(updateErr)
+			switch {
+			case invalid:
+				results.FailedAs(category, org, validationMessage, "Failed to update %s in %s: %s", destination.Name, org, validationMessage)
+			case updateErr != nil:
+				results.Failed(org, updateErr.Error(), "Failed to update %s in %s: %v", destination.Name, org, updateErr)
+			default:
+				results.RecordUpdate(org, destination, changes, permissions)
+				results.Succeeded(org, changes[0].New, "Updated %s in %s: %s", destination.Name, org, changes[0].New)
+			}
+		}
+	})
+
+	progressBar.Stop()
+
+	// Display summary
+	pterm.Println()
+	pterm.DefaultSection.Println("Summary")
+	results.printSummaryCounts("updated")
+
+	// Display command for replication
+	cmd := buildCopyPermissionsReplicationCommand(opts)
+	printReplicationTip("these changes", cmd)
+
+	// Publish the run summary (GitHub Actions step summary, tracking issue)
+	details := []summaryDetail{
+		{Label: "Source Role", Value: source.Name + " (" + opts.sourceOrg + ")"},
+		{Label: "Destination Role", Value: opts.toRole},
+		{Label: "Mode", Value: mode},
+		{Label: "Target Organizations", Value: fmt.Sprintf("%d", len(orgs))},
+		{Label: "Replication Command", Value: "`" + cmd + "`"},
+	}
+	publishRunSummary(opts, "Permission copy: "+source.Name+" → "+opts.toRole, details, results)
+
+	return results.Err()
+}
+
+// resolveSourceRole loads the role to copy from, prompting for it from the
+// organization's custom roles when no name is given
+func resolveSourceRole(hostname, org, name string) (customRole, error) {
+	roles, err := listCustomRoles(hostname, org)
+	if err != nil {
+		return customRole{}, err
+	}
+	if len(roles) == 0 {
+		return customRole{}, fmt.Errorf("organization %s has no custom roles", org)
+	}
+
+	if name == "" {
+		names := make([]string, 0, len(roles))
+		for _, role := range roles {
+			names = append(names, role.Name)
+		}
+		name, err = promptSelect("Select role to copy permissions from", names, "")
+		if err != nil {
+			return customRole{}, err
+		}
+	}
+	for _, role := range roles {
+		if sameRoleName(role.Name, name) {
+			return role, nil
+		}
+	}
+	return customRole{}, fmt.Errorf("organization %s has no role named %s", org, name)
+}
+
+func buildCopyPermissionsReplicationCommand(opts options) string {
+	cmd := "gh custom-roles copy-permissions"
+
+	if opts.hostname != "" {
+		cmd += " --hostname " + shellQuote(opts.hostname)
+	}
+	cmd += targetFlags(opts)
+	cmd += " --from-org " + shellQuote(opts.sourceOrg)
+	cmd += " --from-role " + shellQuote(opts.fromRole)
+	cmd += " --to-role " + shellQuote(opts.toRole)
+	if opts.mergePermissions {
+		cmd += " --merge"
+	}
+	cmd += privilegeGuardFlags(opts)
+	cmd += pacingFlags(opts)
+
+	return cmd
+}
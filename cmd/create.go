@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"errors"
@@ -9,6 +11,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -16,22 +19,98 @@ import (
 
 	"github.com/cli/go-gh/v2"
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/term"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 )
 
 type options struct {
-	hostname    string
-	enterprise  string
-	org         string
-	allOrgs     bool
-	orgsCSVPath string
-	roleName    string
-	roleDesc    string
-	baseRole    string
-	permissions string
-	delay       int
-	concurrency int
+	hostname         string
+	enterprise       string
+	org              string
+	allOrgs          bool
+	orgsCSVPaths     []string
+	orgsFromCmd      string
+	orgsCSVColumn    string
+	roleName         string
+	roleDesc         string
+	baseRole         string
+	permissions      string
+	delay            int
+	concurrency      int
+	mappingPath      string
+	team             string
+	repoTopic        string
+	fromRole         string
+	toRole           string
+	repoPattern      string
+	useEditor        bool
+	preview          bool
+	reportIssue      string
+	requestRate      float64
+	includeAssignees bool
+	findDuplicates   bool
+	findCollisions   bool
+	findStale        bool
+	maxDifference    int
+	maxFailures      string
+	failFast         bool
+	shard            string
+	newRoleName      string
+	repos            []string
+	allRepos         bool
+	outputPath       string
+	// outputFormat is the --format of the running command, copied from
+	// the command's own format field before it runs
+	outputFormat string
+	// confirmEnterprise is the enterprise slug retyped to approve changing
+	// grants across all organizations
+	confirmEnterprise string
+	// interactivePermissions opens the permission prompt even when
+	// --permissions is given, with those permissions checked
+	interactivePermissions bool
+	fromFile               string
+	assumeYes              bool
+	sourceOrg              string
+	mergePermissions       bool
+	adminOnly              bool
+	orgsCreatedAfter       string
+	minRepos               int
+	skipAtQuota            bool
+	roleQuota              int
+	force                  bool
+	reportGist             bool
+	warningsAsErrors       bool
+	user                   string
+	refreshPermissions     bool
+	nameCase               string
+	repo                   string
+	subjectUser            string
+	verifyAuditLog         bool
+	reconcileFix           bool
+	stream                 string
+	paceProfile            string
+	annotations            roleAnnotations
+	hostnames              []string
+	markManaged            bool
+	managedOnly            bool
+	manifestPaths          []string
+	expiringWithin         string
+	upgradeGuard           bool
+	allowPrivilegeIncrease bool
+	missingAny             bool
+	missingRole            string
+	recordChanges          bool
+	assignmentsFormat      string
+	whoCanFormat           string
+	grantMatrixFormat      string
+	retries                int
+	jitter                 float64
+	permissionSearch       string
+	permissionCategory     string
+	reportFormat           string
+	viewFormat             string
+	auditFormat            string
 }
 
 type fineGrainedPermission struct {
@@ -40,7 +119,13 @@ type fineGrainedPermission struct {
 }
 
 type customRole struct {
-	Name string `json:"name"`
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	BaseRole    string   `json:"base_role"`
+	Permissions []string `json:"permissions"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
 }
 
 type customRolesResponse struct {
@@ -51,9 +136,10 @@ type customRolesResponse struct {
 var opts options
 
 var createCmd = &cobra.Command{
-	Use:   "create",
-	Short: "Create custom repository roles in GitHub organizations",
-	RunE:  runCreate,
+	Use:         "create",
+	Short:       "Create custom repository roles in GitHub organizations",
+	RunE:        runCreate,
+	Annotations: mutatingCommand,
 }
 
 func init() {
@@ -62,54 +148,32 @@ func init() {
 	createCmd.Flags().StringVarP(&opts.roleDesc, "role-description", "d", "", "Custom role description")
 	createCmd.Flags().StringVarP(&opts.baseRole, "base-role", "b", "", "Base role (read, triage, write, maintain)")
 	createCmd.Flags().StringVarP(&opts.permissions, "permissions", "p", "", "Comma-separated list of permission names")
+	createCmd.Flags().BoolVar(&opts.preview, "preview", false, "Show what the pre-check found in every target organization, and the API cost of the run, before confirming")
+	createCmd.Flags().BoolVar(&opts.useEditor, "editor", false, "Enter the role description and permissions in your editor instead of prompts")
+	createCmd.Flags().BoolVar(&opts.interactivePermissions, "interactive-permissions", false, "Open the permission list with the --permissions entries already checked")
+	createCmd.Flags().StringVarP(&opts.fromFile, "from-file", "f", "", "Read the role name, description, base role, and permissions from a YAML or JSON file (- for stdin)")
+	createCmd.Flags().BoolVarP(&opts.assumeYes, "yes", "y", false, "Skip the confirmation prompt")
+	createCmd.Flags().BoolVar(&opts.force, "force", false, "Update existing roles with a different definition to match instead of skipping them")
+	createCmd.Flags().StringVar(&opts.confirmEnterprise, "confirm-enterprise", "", "Enterprise slug, retyped to approve updating existing roles with --force and --all-orgs")
+	createCmd.Flags().BoolVar(&opts.markManaged, "mark-managed", false, "Mark the role as managed by gh-custom-roles by ending its description with "+managedMarker)
+	createCmd.Flags().BoolVar(&opts.skipAtQuota, "skip-at-quota", false, "Skip organizations that already have --role-quota custom roles instead of letting the creation fail")
+	createCmd.Flags().BoolVar(&opts.verifyAuditLog, "verify-audit-log", false, "After the run, check the audit log for a role creation event by your account in every organization the role was created in")
+	createCmd.Flags().IntVar(&opts.roleQuota, "role-quota", defaultRoleQuota, "Maximum number of custom repository roles an organization can hold (with --skip-at-quota)")
+	createCmd.MarkFlagsMutuallyExclusive("editor", "interactive-permissions")
+	for _, flag := range []string{"role-name", "role-description", "base-role", "permissions", "editor", "interactive-permissions"} {
+		createCmd.MarkFlagsMutuallyExclusive("from-file", flag)
+	}
 }
 
 func runCreate(_ *cobra.Command, _ []string) error {
 	var err error
-	if opts.hostname == "" {
-		input := pterm.DefaultInteractiveTextInput
-		opts.hostname, err = input.Show("GitHub hostname (press enter for github.com)")
-		if err != nil {
-			return err
-		}
-		opts.hostname = strings.TrimSpace(opts.hostname)
-		if opts.hostname == "" {
-			opts.hostname = "github.com"
-		}
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
 	}
 
-	if opts.org == "" && !opts.allOrgs && opts.orgsCSVPath == "" {
-		selectInput := pterm.DefaultInteractiveSelect.WithOptions([]string{"Single organization", "All organizations in enterprise", "CSV file"})
-		mode, modeErr := selectInput.Show("Select target organizations")
-		if modeErr != nil {
-			return modeErr
-		}
-		switch mode {
-		case "Single organization":
-			input := pterm.DefaultInteractiveTextInput
-			opts.org, err = input.Show("Organization name")
-			if err != nil {
-				return err
-			}
-			opts.org = normalizeOrg(opts.org)
-			if opts.org == "" {
-				return errors.New("organization name is required")
-			}
-		case "All organizations in enterprise":
-			opts.allOrgs = true
-		case "CSV file":
-			input := pterm.DefaultInteractiveTextInput
-			opts.orgsCSVPath, err = input.Show("Path to CSV file")
-			if err != nil {
-				return err
-			}
-			opts.orgsCSVPath = strings.TrimSpace(opts.orgsCSVPath)
-			if opts.orgsCSVPath == "" {
-				return errors.New("CSV file path is required")
-			}
-		default:
-			return errors.New("invalid target selection")
-		}
+	if err := selectTargets(); err != nil {
+		return err
 	}
 
 	// Validate GitHub environment (GHES version and OAuth scopes)
@@ -117,37 +181,30 @@ func runCreate(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
-	// Only prompt for enterprise slug if targeting all organizations
-	if opts.allOrgs {
-		if opts.enterprise == "" {
-			input := pterm.DefaultInteractiveTextInput
-			opts.enterprise, err = input.Show("GitHub enterprise slug (press enter for github)")
-			if err != nil {
-				return err
-			}
-			opts.enterprise = strings.TrimSpace(opts.enterprise)
-			if opts.enterprise == "" {
-				opts.enterprise = "github"
-			}
-		}
-	} else {
-		// Clear enterprise if not targeting all orgs
-		opts.enterprise = ""
+	if err := resolveEnterprise(); err != nil {
+		return err
 	}
 
-	orgs, err := resolveOrganizations(opts)
+	// Organizations are streamed to the workers as they are resolved, so
+	// enterprise pages keep loading while the role details are entered
+	targets, err := openTargetStream(opts)
 	if err != nil {
 		return err
 	}
-	if len(orgs) == 0 {
+	if targets.Total == 0 || targets.First == "" {
 		return errors.New("no organizations provided")
 	}
 
-	validOrgs := orgs
+	if opts.fromFile != "" {
+		definition, err := loadRoleFile(opts.fromFile)
+		if err != nil {
+			return err
+		}
+		applyRoleFile(&opts, definition)
+	}
 
 	if opts.roleName == "" {
-		input := pterm.DefaultInteractiveTextInput
-		opts.roleName, err = input.Show("Custom role name")
+		opts.roleName, err = promptText("Custom role name")
 		if err != nil {
 			return err
 		}
@@ -157,221 +214,473 @@ func runCreate(_ *cobra.Command, _ []string) error {
 		return errors.New("role name is required")
 	}
 
-	if opts.roleDesc == "" {
-		input := pterm.DefaultInteractiveTextInput
-		opts.roleDesc, err = input.Show("Role description (optional)")
+	// A definition file may leave the description empty on purpose
+	if opts.roleDesc == "" && opts.fromFile == "" {
+		if opts.useEditor {
+			opts.roleDesc, err = editDescription(opts.roleName, opts.roleDesc)
+		} else {
+			opts.roleDesc, err = promptText("Role description (optional)")
+		}
 		if err != nil {
 			return err
 		}
 	}
+	if opts.markManaged {
+		opts.roleDesc = markManaged(opts.roleDesc)
+	}
 
 	baseRole, err := resolveBaseRole(opts.baseRole)
 	if err != nil {
 		return err
 	}
 
-	permissions, err := listFineGrainedPermissions(opts.hostname, validOrgs[0])
+	permissions, err := listFineGrainedPermissions(opts.hostname, targets.First)
 	if err != nil {
 		return err
 	}
+	if opts.permissions == "" && opts.useEditor {
+		opts.permissions, err = editPermissions(permissions, nil)
+		if err != nil {
+			return err
+		}
+	}
 	selectedPermissions, err := resolvePermissions(opts.permissions, permissions)
 	if err != nil {
 		return err
 	}
+	if opts.interactivePermissions && opts.permissions != "" {
+		// Start from the flag's permissions and let the user adjust them
+		selectedPermissions, err = selectPermissions(permissions, selectedPermissions)
+		if err != nil {
+			return err
+		}
+	}
 
 	// Display confirmation before creating roles
 	pterm.Println()
 	pterm.DefaultSection.Println("Confirmation")
+	printAccount(opts.hostname)
 	pterm.Info.Printfln("Role Name: %s", opts.roleName)
 	if opts.roleDesc != "" {
 		pterm.Info.Printfln("Description: %s", opts.roleDesc)
 	}
 	pterm.Info.Printfln("Base Role: %s", baseRole)
 	pterm.Info.Printfln("Permissions: %s", strings.Join(selectedPermissions, ", "))
-	pterm.Info.Printfln("Target Organizations: %d", len(validOrgs))
-	pterm.Println()
+	for _, annotation := range opts.annotations.details() {
+		pterm.Info.Printfln("%s: %s", annotation.Label, annotation.Value)
+	}
+	pterm.Info.Printfln("Target Organizations: %d", targets.Total)
+	if opts.force {
+		pterm.Warning.Println("Existing roles with a different definition will be updated to match (--force)")
+		if opts.upgradeGuard && !opts.allowPrivilegeIncrease {
+			pterm.Info.Println("Updates that raise the base role or add sensitive permissions will be blocked (--base-role-upgrade-guard)")
+		}
+	}
 
-	// Validate concurrency bounds
-	if opts.concurrency < 1 || opts.concurrency > 20 {
-		return fmt.Errorf("concurrency must be between 1 and 20 (got %d)", opts.concurrency)
+	// Each organization needs an existence check and a create request. With
+	// --preview, the estimate follows the pre-check instead.
+	if !opts.preview {
+		checkRateLimitBudget(opts.hostname, targets.Total*2)
 	}
+	pterm.Println()
 
-	// Validate delay is non-negative
-	if opts.delay < 0 {
-		return fmt.Errorf("delay must be non-negative (got %d)", opts.delay)
+	// Validate concurrency and delay bounds
+	if err := validatePacing(opts); err != nil {
+		return err
+	}
+	if opts.roleQuota < 1 {
+		return fmt.Errorf("--role-quota must be at least 1 (got %d)", opts.roleQuota)
 	}
 
-	confirm, err := pterm.DefaultInteractiveConfirm.Show("Begin role creation?")
+	// Check which organizations already have the role, or the same
+	// definition under another name, before confirming
+	orgs, err := targets.Collect()
+	if err != nil {
+		return err
+	}
+	check, err := preflightRoles(opts, orgs, baseRole, selectedPermissions)
 	if err != nil {
 		return err
 	}
+	if opts.preview {
+		if err := printCostEstimate(opts, estimateCreateCost(opts, check, baseRole, selectedPermissions)); err != nil {
+			return err
+		}
+		pterm.Println()
+	}
+
+	confirm := opts.assumeYes
+	if !confirm {
+		confirm, err = confirmRun("Begin role creation?")
+		if err != nil {
+			return err
+		}
+	}
 	if !confirm {
 		pterm.Info.Println("Role creation cancelled.")
 		return nil
 	}
+	if opts.force {
+		if err := confirmEnterprise(opts, "update existing roles named "+opts.roleName); err != nil {
+			return err
+		}
+	}
 	pterm.Println()
 
-	progressBar, err := pterm.DefaultProgressbar.WithTotal(len(validOrgs)).WithTitle("Creating custom roles").Start()
-	if err != nil {
-		return err
+	savePromptDefaults(opts, baseRole)
+
+	results := newRunResults(targets.Total)
+
+	// Organizations the role was created in, for --verify-audit-log
+	var createdMu sync.Mutex
+	var created []string
+
+	// With --force, organizations holding an outdated definition of the role
+	// are sent to the creation pass to be updated instead
+	var outdatedMu sync.Mutex
+	outdated := map[string]customRole{}
+
+	// recordCheck reports an organization that will not be created and
+	// returns whether the role still needs to be created or updated
+	recordCheck := func(org string, existing *customRole, roleCount int, existsErr error) bool {
+		var increase string
+		if existing != nil {
+			increase = blockedPrivilegeIncrease(*existing, baseRole, selectedPermissions)
+		}
+		switch {
+		case existsErr != nil && isNotFoundError(existsErr):
+			results.Skipped(org, "Organization not found", "Organization %s not found. Skipping.", org)
+		case isPlanUnsupportedError(existsErr):
+			results.SkippedAs(categoryPlanUnsupported, org, "Custom roles not available on this plan", "Organization %s cannot use custom roles: %s. Skipping.", org, planRequirement)
+		case existsErr != nil:
+			results.Failed(org, existsErr.Error(), "Failed to check existing roles for %s: %v", org, existsErr)
+		case existing != nil && !opts.force:
+			results.Skipped(org, "Role already exists", "Organization %s already has a role named %s. Skipping.", org, opts.roleName)
+		case existing != nil && excludedByManagedOnly(*existing):
+			results.SkippedAs(categoryUnmanaged, org, "Role not managed by gh-custom-roles", "Role %s in %s is not managed by gh-custom-roles. Skipping (--managed-only).", existing.Name, org)
+		case existing != nil && len(roleChanges(*existing, existing.Name, opts.roleDesc, baseRole, selectedPermissions)) == 0:
+			results.Skipped(org, "Role already up to date", "Organization %s already has an identical role named %s. Skipping.", org, existing.Name)
+		case increase != "":
+			results.FailedAs(categoryPrivilegeIncrease, org, "Privilege increase blocked: "+increase, "Not updating role %s in %s: %s (pass --allow-privilege-increase to apply it)", existing.Name, org, increase)
+		case existing != nil:
+			outdatedMu.Lock()
+			outdated[org] = *existing
+			outdatedMu.Unlock()
+			return true
+		case opts.skipAtQuota && roleCount >= opts.roleQuota:
+			results.SkippedAs(categoryAtQuota, org, fmt.Sprintf("%d of %d custom roles", roleCount, opts.roleQuota), "Organization %s already has %d of %d custom roles. Skipping.", org, roleCount, opts.roleQuota)
+		default:
+			return true
+		}
+		return false
 	}
-	defer progressBar.Stop()
 
-	successCount := 0
-	warningCount := 0
-	errorCount := 0
-
-	// If delay is set, use sequential processing with delays
-	if opts.delay > 0 {
-		for i, org := range validOrgs {
-			exists, existsErr := roleExists(opts.hostname, org, opts.roleName)
-			if existsErr != nil {
-				// Check if it's a 404 (org not found)
-				if isNotFoundError(existsErr) {
-					pterm.Warning.Printfln("Organization %s not found. Skipping.", org)
-					warningCount++
-					progressBar.Increment()
-					continue
-				}
-				pterm.Error.Printfln("Failed to check existing roles for %s: %v", org, existsErr)
-				errorCount++
-				progressBar.Increment()
-				continue
-			}
-			if exists {
-				pterm.Warning.Printfln("Organization %s already has a role named %s. Skipping.", org, opts.roleName)
-				warningCount++
-				progressBar.Increment()
-				continue
-			}
+	updateRole := func(org string, existing customRole) {
+		changes := roleChanges(existing, existing.Name, opts.roleDesc, baseRole, selectedPermissions)
+		updateErr := updateCustomRole(opts.hostname, org, existing.ID, changes, selectedPermissions)
+		category, validationMessage, invalid := validationFailure(updateErr)
+		switch {
+		case invalid:
+			results.FailedAs(category, org, validationMessage, "Failed to update role in %s: %s", org, validationMessage)
+		case updateErr != nil:
+			results.Failed(org, updateErr.Error(), "Failed to update role in %s: %v", org, updateErr)
+		default:
+			results.RecordUpdate(org, existing, changes, selectedPermissions)
+			diff := formatRoleChanges(changes)
+			results.Succeeded(org, "Role updated: "+diff, "Updated role %s in %s: %s", existing.Name, org, diff)
+		}
+	}
 
-			createErr := createCustomRole(opts.hostname, org, opts.roleName, opts.roleDesc, baseRole, selectedPermissions)
-			if createErr != nil {
-				// Check if it's a 404 (org not found)
-				if isNotFoundError(createErr) {
-					pterm.Warning.Printfln("Organization %s not found. Skipping.", org)
-					warningCount++
-					progressBar.Increment()
-					continue
-				}
-				pterm.Error.Printfln("Failed to create role in %s: %v", org, createErr)
-				errorCount++
-				progressBar.Increment()
-				continue
-			}
-			pterm.Success.Printfln("Created role %s in %s", opts.roleName, org)
-			successCount++
-			progressBar.Increment()
+	createRole := func(org string) {
+		outdatedMu.Lock()
+		existing, isOutdated := outdated[org]
+		outdatedMu.Unlock()
+		if isOutdated {
+			updateRole(org, existing)
+			return
+		}
 
-			// Add delay between requests (except after the last one)
-			if i < len(validOrgs)-1 {
-				time.Sleep(time.Duration(opts.delay) * time.Second)
-			}
+		createErr := createCustomRole(opts.hostname, org, opts.roleName, opts.roleDesc, baseRole, selectedPermissions)
+		category, validationMessage, invalid := validationFailure(createErr)
+		switch {
+		case createErr != nil && isNotFoundError(createErr):
+			results.Skipped(org, "Organization not found", "Organization %s not found. Skipping.", org)
+		case isPlanUnsupportedError(createErr):
+			results.SkippedAs(categoryPlanUnsupported, org, "Custom roles not available on this plan", "Organization %s cannot use custom roles: %s. Skipping.", org, planRequirement)
+		case invalid:
+			results.FailedAs(category, org, validationMessage, "Failed to create role in %s: %s", org, validationMessage)
+		case createErr != nil:
+			results.Failed(org, createErr.Error(), "Failed to create role in %s: %v", org, createErr)
+		default:
+			results.Succeeded(org, "Role created", "Created role %s in %s", opts.roleName, org)
+			createdMu.Lock()
+			created = append(created, org)
+			createdMu.Unlock()
 		}
-	} else {
-		// Use concurrent processing with semaphore
-		var wg sync.WaitGroup
-		var mu sync.Mutex
-		semaphore := make(chan struct{}, opts.concurrency)
-
-		for _, org := range validOrgs {
-			wg.Add(1)
-			semaphore <- struct{}{} // Acquire semaphore
-
-			go func(org string) {
-				defer wg.Done()
-				defer func() { <-semaphore }() // Release semaphore
-
-				exists, existsErr := roleExists(opts.hostname, org, opts.roleName)
-				if existsErr != nil {
-					mu.Lock()
-					if isNotFoundError(existsErr) {
-						pterm.Warning.Printfln("Organization %s not found. Skipping.", org)
-						warningCount++
-					} else {
-						pterm.Error.Printfln("Failed to check existing roles for %s: %v", org, existsErr)
-						errorCount++
-					}
-					progressBar.Increment()
-					mu.Unlock()
-					return
-				}
-				if exists {
-					mu.Lock()
-					pterm.Warning.Printfln("Organization %s already has a role named %s. Skipping.", org, opts.roleName)
-					warningCount++
-					progressBar.Increment()
-					mu.Unlock()
-					return
-				}
+	}
 
-				createErr := createCustomRole(opts.hostname, org, opts.roleName, opts.roleDesc, baseRole, selectedPermissions)
-				mu.Lock()
-				if createErr != nil {
-					if isNotFoundError(createErr) {
-						pterm.Warning.Printfln("Organization %s not found. Skipping.", org)
-						warningCount++
-					} else {
-						pterm.Error.Printfln("Failed to create role in %s: %v", org, createErr)
-						errorCount++
-					}
-				} else {
-					pterm.Success.Printfln("Created role %s in %s", opts.roleName, org)
-					successCount++
-				}
-				progressBar.Increment()
-				mu.Unlock()
-			}(org)
+	// Every organization was already checked before confirmation
+	var queue []string
+	for _, org := range check.Inaccessible {
+		recordCheck(org, nil, 0, check.Errors[org])
+	}
+	for _, org := range check.Exists {
+		existing := check.Existing[org]
+		if recordCheck(org, &existing, check.RoleCounts[org], nil) {
+			queue = append(queue, org)
 		}
+	}
+	for _, org := range check.AtQuota {
+		recordCheck(org, nil, check.RoleCounts[org], nil)
+	}
+	queue = append(queue, check.Create...)
 
-		wg.Wait()
+	progressBar, err := startProgressbar(len(queue), "Creating custom roles")
+	if err != nil {
+		return err
 	}
+	defer progressBar.Stop()
+	results.Track(progressBar)
 
+	processQueue(opts, untilStopped(streamQueued(queueTargets(queue)), results.Stopped()), createRole)
 	progressBar.Stop()
 
 	// Display summary
 	pterm.Println()
 	pterm.DefaultSection.Println("Summary")
-	pterm.Info.Printfln("✓ Successfully created: %d", successCount)
-	if warningCount > 0 {
-		pterm.Warning.Printfln("⚠ Warnings: %d", warningCount)
-	}
-	if errorCount > 0 {
-		pterm.Error.Printfln("✗ Errors: %d", errorCount)
+	results.printSummaryCounts("created")
+
+	var auditDetail summaryDetail
+	unverified := 0
+	if opts.verifyAuditLog {
+		auditDetail, unverified = auditLogVerification(opts, opts.roleName, created, results.started)
 	}
 
 	// Display command for replication
-	pterm.Println()
-	pterm.FgMagenta.Println("💡 Tip: To replicate these changes without the interactive process, use:")
-	pterm.Println()
 	cmd := buildReplicationCommand(opts, baseRole, selectedPermissions)
-	pterm.Println(cmd)
-	pterm.Println()
+	printReplicationTip("these changes", cmd)
 
-	if errorCount > 0 {
-		return fmt.Errorf("completed with %d errors", errorCount)
+	// Publish the run summary (GitHub Actions step summary, tracking issue)
+	details := []summaryDetail{
+		{Label: "Role Name", Value: opts.roleName},
+		{Label: "Base Role", Value: baseRole},
+		{Label: "Permissions", Value: strings.Join(selectedPermissions, ", ")},
+	}
+	details = append(details, opts.annotations.details()...)
+	details = append(details,
+		summaryDetail{Label: "Target Organizations", Value: fmt.Sprintf("%d", targets.Total)},
+		summaryDetail{Label: "Replication Command", Value: "`" + cmd + "`"},
+	)
+	if opts.verifyAuditLog {
+		details = append(details, auditDetail)
+	}
+	publishRunSummary(opts, "Custom role creation: "+opts.roleName, details, results)
+
+	if err := results.Err(); err != nil {
+		return err
+	}
+	if unverified > 0 {
+		return fmt.Errorf("%d role creations could not be verified against the audit log", unverified)
 	}
 	return nil
 }
 
-func resolveOrganizations(opts options) ([]string, error) {
-	if opts.allOrgs {
-		return fetchOrganizations(opts.hostname, opts.enterprise)
+// resolveHostname returns the host to run against, prompting when needed,
+// and selects the gh account to use on it
+func resolveHostname(hostname string) (string, error) {
+	hostname, err := promptHostname(hostname)
+	if err != nil {
+		return "", err
 	}
-	if opts.org != "" {
-		return []string{normalizeOrg(opts.org)}, nil
+	if err := selectAccount(hostname); err != nil {
+		return "", err
+	}
+	// Pacing profiles are tuned per host type
+	applyPaceProfile(hostname)
+	return hostname, nil
+}
+
+func promptHostname(hostname string) (string, error) {
+	if hostname != "" {
+		return normalizeHostname(hostname), nil
+	}
+
+	// GH_HOST selects the host for every gh command, so it is used as is
+	defaultHostname, source := ghDefaultHost()
+	if source == "GH_HOST" {
+		pterm.Info.Printfln("Using host %s from GH_HOST", defaultHostname)
+		return normalizeHostname(defaultHostname), nil
+	}
+	if savedDefaults.Hostname != "" {
+		defaultHostname = savedDefaults.Hostname
+	}
+
+	hostname, err := promptText(fmt.Sprintf("GitHub hostname (press enter for %s)", defaultHostname))
+	if err != nil {
+		return "", err
+	}
+	hostname = strings.TrimSpace(hostname)
+	if hostname == "" {
+		hostname = defaultHostname
+	}
+	return normalizeHostname(hostname), nil
+}
+
+// selectTargets prompts for the organization targeting mode when no target flag is set
+func selectTargets() error {
+	if opts.org != "" || opts.allOrgs || len(opts.orgsCSVPaths) > 0 || opts.orgsFromCmd != "" {
+		return nil
+	}
+
+	mode, err := promptSelect("Select target organizations", []string{targetModeSingle, targetModeAll, targetModeCSV}, savedDefaults.TargetMode)
+	if err != nil {
+		return err
+	}
+	switch mode {
+	case targetModeSingle:
+		opts.org, err = promptText("Organization name")
+		if err != nil {
+			return err
+		}
+		opts.org = normalizeOrg(opts.org)
+		if opts.org == "" {
+			return errors.New("organization name is required")
+		}
+	case targetModeAll:
+		opts.allOrgs = true
+	case targetModeCSV:
+		path, err := promptText("Path to CSV file or directory of CSV files")
+		if err != nil {
+			return err
+		}
+		path = strings.TrimSpace(path)
+		if path == "" {
+			return errors.New("CSV file path is required")
+		}
+		opts.orgsCSVPaths = []string{path}
+	default:
+		return errors.New("invalid target selection")
+	}
+	return nil
+}
+
+// resolveEnterprise prompts for the enterprise slug when targeting all organizations
+func resolveEnterprise() error {
+	// Clear enterprise if not targeting all orgs
+	if !opts.allOrgs {
+		opts.enterprise = ""
+		return nil
+	}
+	if opts.enterprise != "" {
+		return nil
+	}
+
+	// GHE.com subdomains identify the enterprise, so prefer that over the
+	// previously used slug
+	defaultEnterprise := defaultEnterpriseForHost(opts.hostname)
+	if defaultEnterprise == "" {
+		defaultEnterprise = savedDefaults.Enterprise
+	}
+	if defaultEnterprise == "" {
+		defaultEnterprise = "github"
+	}
+
+	enterprise, err := promptText(fmt.Sprintf("GitHub enterprise slug (press enter for %s)", defaultEnterprise))
+	if err != nil {
+		return err
 	}
-	if opts.orgsCSVPath != "" {
-		return loadOrganizationsFromCSV(opts.orgsCSVPath)
+	opts.enterprise = strings.TrimSpace(enterprise)
+	if opts.enterprise == "" {
+		opts.enterprise = defaultEnterprise
 	}
-	return nil, errors.New("no organization target specified")
+	return nil
+}
+
+func resolveOrganizations(opts options) ([]string, error) {
+	var orgs []string
+	var err error
+	switch {
+	case opts.allOrgs:
+		orgs, err = fetchOrganizations(opts.hostname, opts.enterprise, newOrgFilter(opts))
+	case opts.org != "":
+		orgs = []string{normalizeOrg(opts.org)}
+	case len(opts.orgsCSVPaths) > 0:
+		orgs, err = loadOrganizationsFromCSVs(opts.orgsCSVPaths, opts.orgsCSVColumn)
+	case opts.orgsFromCmd != "":
+		orgs, err = loadOrganizationsFromCommand(opts.orgsFromCmd)
+	default:
+		return nil, errors.New("no organization target specified")
+	}
+	if err == nil && !opts.allOrgs && newOrgFilter(opts).active() {
+		err = errors.New("--orgs-created-after and --min-repos only apply to --all-orgs")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return shardOrganizations(opts, orgs), nil
 }
 
 func normalizeOrg(org string) string {
 	return strings.ToLower(strings.TrimSpace(org))
 }
 
-func loadOrganizationsFromCSV(path string) ([]string, error) {
+// orgsCSVHeaders are header values recognized in the first row of an
+// organizations CSV when no --orgs-csv-column is given
+var orgsCSVHeaders = map[string]bool{"org": true, "orgs": true, "organization": true, "organizations": true, "login": true}
+
+// orgLoginPattern matches valid organization logins: alphanumerics and single
+// hyphens, not starting or ending with a hyphen
+var orgLoginPattern = regexp.MustCompile(`^[a-z0-9](?:-?[a-z0-9])*$`)
+
+// loadOrganizationsFromCSVs reads target organizations from several CSV
+// files, merging them in order without duplicates. A directory contributes
+// every .csv file directly inside it, in name order.
+func loadOrganizationsFromCSVs(paths []string, column string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(filepath.Clean(path))
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		var found []string
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".csv") {
+				found = append(found, filepath.Join(path, entry.Name()))
+			}
+		}
+		if len(found) == 0 {
+			return nil, fmt.Errorf("directory %s contains no .csv files", path)
+		}
+		files = append(files, found...)
+	}
+
+	var orgs []string
+	for _, file := range files {
+		loaded, err := loadOrganizationsFromCSV(file, column)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		orgs = append(orgs, loaded...)
+	}
+	if len(files) > 1 {
+		pterm.Info.Printfln("Read %d organizations from %d CSV files", len(uniqueStrings(orgs)), len(files))
+	}
+	return uniqueStrings(orgs), nil
+}
+
+// loadOrganizationsFromCSV reads target organizations from a CSV file. With a
+// column name, the first row must be a header and only that column is read;
+// otherwise every field is an organization and a recognized header row is
+// skipped. Invalid entries are reported with their row number and skipped.
+func loadOrganizationsFromCSV(path, column string) ([]string, error) {
 	cleanPath := filepath.Clean(path)
 	file, err := os.Open(cleanPath)
 	if err != nil {
@@ -379,21 +688,52 @@ func loadOrganizationsFromCSV(path string) ([]string, error) {
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
-	reader.FieldsPerRecord = -1
+	reader := newCSVReader(file)
 	records, err := reader.ReadAll()
 	if err != nil {
 		return nil, err
 	}
 
+	columnIndex := -1
+	if column != "" {
+		if len(records) == 0 {
+			return nil, fmt.Errorf("CSV file %s is empty; expected a header row with column %q", path, column)
+		}
+		for i, header := range records[0] {
+			if strings.EqualFold(strings.TrimSpace(header), strings.TrimSpace(column)) {
+				columnIndex = i
+				break
+			}
+		}
+		if columnIndex < 0 {
+			return nil, fmt.Errorf("CSV file %s has no column named %q (found: %s)", path, column, strings.Join(records[0], ", "))
+		}
+	}
+
 	orgSet := map[string]bool{}
 	var orgs []string
-	for _, record := range records {
-		for _, value := range record {
+	for i, record := range records {
+		row := i + 1
+		if row == 1 && (columnIndex >= 0 || isOrgsCSVHeader(record)) {
+			continue
+		}
+
+		values := record
+		if columnIndex >= 0 {
+			if columnIndex >= len(record) {
+				continue
+			}
+			values = record[columnIndex : columnIndex+1]
+		}
+		for _, value := range values {
 			org := normalizeOrg(value)
 			if org == "" || orgSet[org] {
 				continue
 			}
+			if !orgLoginPattern.MatchString(org) || len(org) > 39 {
+				pterm.Warning.Printfln("CSV row %d: skipping %q (not a valid organization name)", row, strings.TrimSpace(value))
+				continue
+			}
 			orgSet[org] = true
 			orgs = append(orgs, org)
 		}
@@ -401,6 +741,24 @@ func loadOrganizationsFromCSV(path string) ([]string, error) {
 	return orgs, nil
 }
 
+func isOrgsCSVHeader(record []string) bool {
+	return len(record) > 0 && orgsCSVHeaders[strings.ToLower(strings.TrimSpace(record[0]))]
+}
+
+// newCSVReader returns a CSV reader for user-supplied files. A UTF-8 byte
+// order mark, as written by Excel, is skipped; rows may have any number of
+// fields and quoted fields may contain commas.
+func newCSVReader(file io.Reader) *csv.Reader {
+	buffered := bufio.NewReader(file)
+	if bom, err := buffered.Peek(3); err == nil && string(bom) == "\xef\xbb\xbf" {
+		_, _ = buffered.Discard(3)
+	}
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	return reader
+}
+
 func resolveBaseRole(baseRole string) (string, error) {
 	options := []string{"read", "triage", "write", "maintain"}
 	if baseRole != "" {
@@ -413,8 +771,7 @@ func resolveBaseRole(baseRole string) (string, error) {
 		return "", fmt.Errorf("invalid base role: %s", baseRole)
 	}
 
-	selectInput := pterm.DefaultInteractiveSelect.WithOptions(options)
-	choice, err := selectInput.Show("Select base role")
+	choice, err := promptSelect("Select base role", options, savedDefaults.BaseRole)
 	if err != nil {
 		return "", err
 	}
@@ -433,28 +790,44 @@ func resolvePermissions(flagValue string, permissions []fineGrainedPermission) (
 
 	if strings.TrimSpace(flagValue) != "" {
 		items := strings.Split(flagValue, ",")
-		var selected []string
+		var selected, unknown []string
 		for _, item := range items {
 			name := strings.TrimSpace(item)
 			if name == "" {
 				continue
 			}
 			if !permissionMap[name] {
-				return nil, fmt.Errorf("unknown permission: %s", name)
+				unknown = append(unknown, name)
+				continue
 			}
 			selected = append(selected, name)
 		}
+		if len(unknown) > 0 {
+			printPermissionSuggestions(unknown, permissions)
+			return nil, fmt.Errorf("unknown %s: %s", pluralize(len(unknown), "permission", "permissions"), strings.Join(unknown, ", "))
+		}
 		if len(selected) == 0 {
 			return nil, errors.New("permissions are required")
 		}
 		return uniqueStrings(selected), nil
 	}
 
+	return selectPermissions(permissions, nil)
+}
+
+// selectPermissions prompts for permissions from the catalog, with the
+// current permissions checked to begin with
+func selectPermissions(permissions []fineGrainedPermission, current []string) ([]string, error) {
 	sort.SliceStable(permissions, func(i, j int) bool {
 		return permissions[i].Name < permissions[j].Name
 	})
 
+	checked := map[string]bool{}
+	for _, name := range current {
+		checked[name] = true
+	}
 	options := make([]string, 0, len(permissions))
+	var defaults []string
 	lookup := map[string]string{}
 	for _, perm := range permissions {
 		label := perm.Name
@@ -463,13 +836,12 @@ func resolvePermissions(flagValue string, permissions []fineGrainedPermission) (
 		}
 		options = append(options, label)
 		lookup[label] = perm.Name
+		if checked[perm.Name] {
+			defaults = append(defaults, label)
+		}
 	}
 
-	selection, err := pterm.DefaultInteractiveMultiselect.
-		WithOptions(options).
-		WithFilter(true).
-		WithMaxHeight(10).
-		Show("Select permissions (Type to filter, ↑↓ to navigate, Enter to toggle, Tab to confirm)")
+	selection, err := promptMultiselect("Select permissions", options, defaults)
 	if err != nil {
 		return nil, err
 	}
@@ -497,24 +869,67 @@ func uniqueStrings(values []string) []string {
 	return result
 }
 
-func roleExists(hostname, org, roleName string) (bool, error) {
-	response, stderr, err := ghAPI(hostname, "orgs/"+org+"/custom-repository-roles")
+// findRole returns an organization's role named roleName, or nil if there
+// is none, along with how many custom roles the organization has
+func findRole(hostname, org, roleName string) (*customRole, int, error) {
+	roles, err := listCustomRoles(hostname, org)
 	if err != nil {
-		return false, fmt.Errorf("custom role lookup failed: %w (%s)", err, stderr.String())
+		return nil, 0, err
 	}
+	return matchRole(roles, roleName), len(roles), nil
+}
 
-	var payload customRolesResponse
-	if err := json.Unmarshal(response.Bytes(), &payload); err != nil {
-		return false, err
+// matchRole returns the role named roleName, or nil if there is none
+func matchRole(roles []customRole, roleName string) *customRole {
+	for _, role := range roles {
+		if sameRoleName(role.Name, roleName) {
+			return &role
+		}
+	}
+	return nil
+}
+
+// duplicateRole returns a role with a name other than roleName that already
+// grants the same base role and set of permissions, or nil if there is none
+func duplicateRole(roles []customRole, roleName, baseRole string, permissions []string) *customRole {
+	for _, role := range roles {
+		if sameRoleName(role.Name, roleName) || role.BaseRole != baseRole {
+			continue
+		}
+		if len(roleChanges(role, role.Name, role.Description, baseRole, permissions)) == 0 {
+			return &role
+		}
 	}
+	return nil
+}
 
-	roleName = strings.ToLower(strings.TrimSpace(roleName))
-	for _, role := range payload.Custom {
-		if strings.ToLower(role.Name) == roleName {
-			return true, nil
+// formatRoleChanges describes role changes on one line, such as
+// "Base Role: write → maintain; Permissions: +delete_alerts_code_scanning"
+func formatRoleChanges(changes []roleChange) string {
+	parts := make([]string, 0, len(changes))
+	for _, change := range changes {
+		if change.Field == "Permissions" {
+			parts = append(parts, change.Field+": "+change.New)
+			continue
 		}
+		parts = append(parts, fmt.Sprintf("%s: %q → %q", change.Field, change.Current, change.New))
 	}
-	return false, nil
+	return strings.Join(parts, "; ")
+}
+
+// listCustomRoles returns the custom repository roles defined in an
+// organization
+func listCustomRoles(hostname, org string) ([]customRole, error) {
+	response, stderr, err := ghAPI(hostname, "orgs/"+org+"/custom-repository-roles")
+	if err != nil {
+		return nil, fmt.Errorf("custom role lookup failed: %w (%s)", err, stderr.String())
+	}
+
+	var payload customRolesResponse
+	if err := json.Unmarshal(response.Bytes(), &payload); err != nil {
+		return nil, err
+	}
+	return payload.Custom, nil
 }
 
 func createCustomRole(hostname, org, name, description, baseRole string, permissions []string) error {
@@ -537,89 +952,77 @@ func createCustomRole(hostname, org, name, description, baseRole string, permiss
 	return nil
 }
 
+var (
+	permissionCacheMu sync.Mutex
+	permissionCache   = map[string][]fineGrainedPermission{}
+)
+
+// listFineGrainedPermissions returns the fine-grained permission catalog. The
+// catalog is cached per host for the rest of the process, and on disk for
+// permissionCacheTTL unless --refresh-permissions is set.
 func listFineGrainedPermissions(hostname, org string) ([]fineGrainedPermission, error) {
+	permissionCacheMu.Lock()
+	defer permissionCacheMu.Unlock()
+
+	if cached, ok := permissionCache[hostname]; ok {
+		return append([]fineGrainedPermission(nil), cached...), nil
+	}
+	if cached, ok := loadCachedPermissions(hostname); ok {
+		permissionCache[hostname] = cached
+		return append([]fineGrainedPermission(nil), cached...), nil
+	}
+
+	spinner, err := startSpinner("Fetching fine-grained permissions...")
+	if err != nil {
+		return nil, err
+	}
 	response, stderr, err := ghAPI(hostname, "orgs/"+org+"/repository-fine-grained-permissions")
 	if err != nil {
+		spinner.Fail("Failed to fetch fine-grained permissions")
 		return nil, fmt.Errorf("permissions lookup failed: %w (%s)", err, stderr.String())
 	}
 
 	var permissions []fineGrainedPermission
 	if err := json.Unmarshal(response.Bytes(), &permissions); err != nil {
+		spinner.Fail("Failed to parse fine-grained permissions")
 		return nil, err
 	}
-	return permissions, nil
+	spinner.Success(fmt.Sprintf("Fetched %d fine-grained permissions", len(permissions)))
+
+	permissionCache[hostname] = permissions
+	saveCachedPermissions(hostname, permissions)
+	return append([]fineGrainedPermission(nil), permissions...), nil
 }
 
-func fetchOrganizations(hostname, enterprise string) ([]string, error) {
+func fetchOrganizations(hostname, enterprise string, filter orgFilter) ([]string, error) {
 	if enterprise == "" {
 		return nil, fmt.Errorf("--enterprise flag is required")
 	}
 
 	pterm.Info.Println("Fetching organizations for enterprise...")
 
-	var spinner *pterm.SpinnerPrinter
+	var spinner *spinnerPrinter
 	stopSpinner := func() {
 		if spinner != nil {
 			spinner.Stop()
+			spinner = nil
 		}
 	}
 	defer stopSpinner()
 
-	const maxPerPage = 100
 	var orgs []string
-	var cursor *string
-
-	for {
-		query := `{
-			enterprise(slug: "` + enterprise + `") {
-				organizations(first: ` + fmt.Sprintf("%d", maxPerPage) + `, after: ` + formatCursor(cursor) + `) {
-					nodes {
-						login
-					}
-					pageInfo {
-						hasNextPage
-						endCursor
-					}
-				}
-			}
-		}`
-
-		response, stderr, execErr := gh.Exec("api", "--hostname", hostname, "graphql", "-f", "query="+query)
-		if execErr != nil {
-			pterm.Error.Printf("Failed to fetch organizations for enterprise '%s': %v\n", enterprise, execErr)
-			pterm.Error.Printf("GraphQL query: %s\n", query)
-			pterm.Error.Printf("gh CLI stderr: %s\n", stderr.String())
-			return nil, execErr
-		}
-
-		var result struct {
-			Data struct {
-				Enterprise struct {
-					Organizations struct {
-						Nodes []struct {
-							Login string `json:"login"`
-						}
-						PageInfo struct {
-							HasNextPage bool   `json:"hasNextPage"`
-							EndCursor   string `json:"endCursor"`
-						} `json:"pageInfo"`
-					} `json:"organizations"`
-				} `json:"enterprise"`
-			} `json:"data"`
-		}
-
-		if err := json.Unmarshal(response.Bytes(), &result); err != nil {
-			pterm.Error.Printf("Failed to parse organizations data for enterprise '%s': %v\n", enterprise, err)
-			return nil, err
-		}
-
-		for _, org := range result.Data.Enterprise.Organizations.Nodes {
-			orgs = append(orgs, normalizeOrg(org.Login))
+	total, problems := 0, 0
+	for page := range streamOrganizations(hostname, enterprise, filter) {
+		if page.Err != nil {
+			return nil, page.Err
 		}
+		orgs = append(orgs, page.Logins...)
+		total = page.Total
+		problems += len(page.Problems)
 
 		// Start spinner only after we have successfully fetched at least one page.
 		if spinner == nil {
-			started, err := pterm.DefaultSpinner.Start(fmt.Sprintf("Fetched %d organizations", len(orgs)))
+			started, err := startSpinner(fmt.Sprintf("Fetched %d organizations", len(orgs)))
 			if err != nil {
 				return nil, err
 			}
@@ -627,14 +1030,181 @@ func fetchOrganizations(hostname, enterprise string) ([]string, error) {
 		} else {
 			spinner.UpdateText(fmt.Sprintf("Fetched %d organizations", len(orgs)))
 		}
+	}
 
-		if !result.Data.Enterprise.Organizations.PageInfo.HasNextPage {
-			break
-		}
-		cursor = &result.Data.Enterprise.Organizations.PageInfo.EndCursor
+	stopSpinner()
+	orgs = uniqueStrings(orgs)
+	if problems > 0 {
+		pterm.Warning.Printfln("Listed %d of %d organizations; the listing returned %s (see the warnings above)", len(orgs), total, pluralize(problems, "error", "errors"))
 	}
+	if filter.active() {
+		pterm.Info.Printfln("Filters: %d of %d organizations match %s", len(orgs), total, filter)
+	}
+	return orgs, nil
+}
 
-	return uniqueStrings(orgs), nil
+// organizationPage is one page of enterprise organization logins, or the
+// error that stopped pagination. Logins only holds the organizations that
+// pass the filter, while Total counts the whole enterprise.
+type organizationPage struct {
+	Logins []string
+	// Admin reports whether the viewer can administer each login
+	Admin map[string]bool
+	Total int
+	// Problems are the organizations the page returned errors for
+	Problems []orgProblem
+	Err      error
+}
+
+// enterpriseOrganizations is one page of the enterprise organization query
+type enterpriseOrganizations struct {
+	Enterprise struct {
+		Organizations struct {
+			TotalCount int `json:"totalCount"`
+			Nodes      []struct {
+				Login               string    `json:"login"`
+				ViewerCanAdminister bool      `json:"viewerCanAdminister"`
+				CreatedAt           time.Time `json:"createdAt"`
+				Repositories        struct {
+					TotalCount int `json:"totalCount"`
+				} `json:"repositories"`
+			}
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+		} `json:"organizations"`
+	} `json:"enterprise"`
+}
+
+// hasNodes reports whether the page holds organizations despite any errors
+func (e enterpriseOrganizations) hasNodes() bool {
+	return len(e.Enterprise.Organizations.Nodes) > 0
+}
+
+// logins returns the login of every node on the page, empty for nodes that
+// came back null
+func (e enterpriseOrganizations) logins() []string {
+	logins := make([]string, 0, len(e.Enterprise.Organizations.Nodes))
+	for _, org := range e.Enterprise.Organizations.Nodes {
+		logins = append(logins, normalizeOrg(org.Login))
+	}
+	return logins
+}
+
+// organizationPageBuffer is how many pages the fetcher may run ahead of the
+// consumer
+const organizationPageBuffer = 4
+
+// streamOrganizations fetches the enterprise's organizations in the
+// background and sends each page as soon as it arrives, so callers can
+// process a page while the next one is being fetched. The channel is closed
+// after the last page or the first error.
+func streamOrganizations(hostname, enterprise string, filter orgFilter) <-chan organizationPage {
+	pages := make(chan organizationPage, organizationPageBuffer)
+
+	go func() {
+		defer close(pages)
+
+		client, err := graphqlClient(hostname)
+		if err != nil {
+			pages <- organizationPage{Err: err}
+			return
+		}
+
+		const maxPerPage = 100
+		var cursor *string
+
+		for {
+			query := `{
+				enterprise(slug: "` + enterprise + `") {
+					organizations(first: ` + fmt.Sprintf("%d", maxPerPage) + `, after: ` + formatCursor(cursor) + `) {
+						totalCount
+						nodes {
+							login
+							viewerCanAdminister
+							createdAt
+							repositories {
+								totalCount
+							}
+						}
+						pageInfo {
+							hasNextPage
+							endCursor
+						}
+					}
+				}
+			}`
+
+			var result enterpriseOrganizations
+			err := client.Do(query, nil, &result)
+
+			// A partial response, for example when some organizations
+			// enforce SAML single sign-on, holds the rest of the page. It is
+			// retried in case the errors are transient, then used as is with
+			// the affected organizations reported.
+			var partial *api.GraphQLError
+			for attempt := 1; errors.As(err, &partial) && result.hasNodes() && attempt < graphQLPartialAttempts; attempt++ {
+				time.Sleep(time.Duration(attempt) * graphQLPartialRetryInterval)
+				result = enterpriseOrganizations{}
+				err = client.Do(query, nil, &result)
+			}
+			var problems []orgProblem
+			if errors.As(err, &partial) && result.hasNodes() {
+				var general []string
+				problems, general = orgProblems(partial, result.logins())
+				if len(general) == 0 {
+					err = nil
+				} else {
+					// Errors outside the organization nodes, such as on
+					// pageInfo, would end pagination early
+					err = fmt.Errorf("incomplete organization listing: %s", strings.Join(general, "; "))
+				}
+			}
+			if err != nil {
+				pterm.Error.Printf("Failed to fetch organizations for enterprise '%s': %v\n", enterprise, err)
+				pterm.Error.Printf("GraphQL query: %s\n", query)
+				pages <- organizationPage{Err: err}
+				return
+			}
+			if len(problems) > 0 {
+				reportOrgProblems(enterprise, problems)
+			}
+			// Organizations whose filter fields could not be read are kept,
+			// since leaving them out would hide them from the run
+			unfiltered := map[int]bool{}
+			for _, problem := range problems {
+				unfiltered[problem.Index] = true
+			}
+
+			logins := make([]string, 0, len(result.Enterprise.Organizations.Nodes))
+			admin := make(map[string]bool, len(result.Enterprise.Organizations.Nodes))
+			for i, org := range result.Enterprise.Organizations.Nodes {
+				if org.Login == "" {
+					// The organization itself could not be read
+					continue
+				}
+				if !unfiltered[i] && !filter.match(org.CreatedAt, org.Repositories.TotalCount) {
+					continue
+				}
+				login := normalizeOrg(org.Login)
+				logins = append(logins, login)
+				admin[login] = org.ViewerCanAdminister
+			}
+
+			// The buffered send lets the next request start while the
+			// consumer is still handling this page
+			pages <- organizationPage{Logins: logins, Admin: admin, Total: result.Enterprise.Organizations.TotalCount, Problems: problems}
+
+			pageInfo := result.Enterprise.Organizations.PageInfo
+			if !pageInfo.HasNextPage {
+				return
+			}
+			cursor = &pageInfo.EndCursor
+		}
+	}()
+
+	return pages
 }
 
 func formatCursor(cursor *string) string {
@@ -644,10 +1214,22 @@ func formatCursor(cursor *string) string {
 	return `"` + *cursor + `"`
 }
 
-func ghAPI(hostname string, args ...string) (bytes.Buffer, bytes.Buffer, error) {
-	fullArgs := []string{"api", "--hostname", hostname, "-H", "Accept: application/vnd.github+json", "-H", "X-GitHub-Api-Version: 2022-11-28"}
-	fullArgs = append(fullArgs, args...)
-	return gh.Exec(fullArgs...)
+// decodeArrayPages decodes paginated gh api output, which may contain one JSON
+// array per page, into a single slice
+func decodeArrayPages[T any](data []byte) ([]T, error) {
+	var items []T
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var page []T
+		if err := decoder.Decode(&page); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		items = append(items, page...)
+	}
+	return items, nil
 }
 
 func isNotFoundError(err error) bool {
@@ -658,67 +1240,148 @@ func isNotFoundError(err error) bool {
 	return strings.Contains(errorText, "404") || strings.Contains(errorText, "not found")
 }
 
-func buildReplicationCommand(opts options, baseRole string, permissions []string) string {
-	cmd := "gh custom-roles create"
-
-	if opts.hostname != "" {
-		cmd += " --hostname " + opts.hostname
-	}
+// targetFlags returns the replication command flags that select the target
+// organizations
+func targetFlags(opts options) string {
+	var flags string
 	if opts.enterprise != "" {
-		cmd += " --enterprise " + opts.enterprise
+		flags += " --enterprise " + shellQuote(opts.enterprise)
 	}
 	if opts.org != "" {
-		cmd += " --org " + opts.org
+		flags += " --org " + shellQuote(opts.org)
 	} else if opts.allOrgs {
-		cmd += " --all-orgs"
-	} else if opts.orgsCSVPath != "" {
-		cmd += " --orgs-csv " + opts.orgsCSVPath
+		flags += " --all-orgs"
+		if opts.orgsCreatedAfter != "" {
+			flags += " --orgs-created-after " + shellQuote(opts.orgsCreatedAfter)
+		}
+		if opts.minRepos > 0 {
+			flags += fmt.Sprintf(" --min-repos %d", opts.minRepos)
+		}
+	} else if len(opts.orgsCSVPaths) > 0 {
+		for _, path := range opts.orgsCSVPaths {
+			flags += " --orgs-csv " + shellQuote(path)
+		}
+		if opts.orgsCSVColumn != "" {
+			flags += " --orgs-csv-column " + shellQuote(opts.orgsCSVColumn)
+		}
+	} else if opts.orgsFromCmd != "" {
+		flags += " --orgs-from-cmd " + shellQuote(opts.orgsFromCmd)
 	}
+	if opts.shard != "" {
+		flags += " --shard " + opts.shard
+	}
+	if opts.managedOnly {
+		flags += " --managed-only"
+	}
+	return flags
+}
+
+func buildReplicationCommand(opts options, baseRole string, permissions []string) string {
+	cmd := "gh custom-roles create"
+
+	if opts.hostname != "" {
+		cmd += " --hostname " + shellQuote(opts.hostname)
+	}
+	cmd += targetFlags(opts)
 	if opts.roleName != "" {
-		cmd += " --role-name '" + opts.roleName + "'"
+		cmd += " --role-name " + shellQuote(opts.roleName)
 	}
 	if opts.roleDesc != "" {
-		cmd += " --role-description '" + opts.roleDesc + "'"
+		cmd += " --role-description " + shellQuote(opts.roleDesc)
 	}
 	if baseRole != "" {
-		cmd += " --base-role " + baseRole
+		cmd += " --base-role " + shellQuote(baseRole)
 	}
 	if len(permissions) > 0 {
-		permStr := strings.Join(permissions, ",")
-		cmd += " --permissions " + permStr
+		cmd += " --permissions " + shellQuote(strings.Join(permissions, ","))
 	}
-	if opts.delay > 0 {
-		cmd += fmt.Sprintf(" --delay %d", opts.delay)
+	if opts.force {
+		cmd += " --force"
 	}
-	if opts.concurrency > 1 {
-		cmd += fmt.Sprintf(" --concurrency %d", opts.concurrency)
+	cmd += privilegeGuardFlags(opts)
+	if opts.markManaged {
+		cmd += " --mark-managed"
+	}
+	if opts.verifyAuditLog {
+		cmd += " --verify-audit-log"
+	}
+	if opts.skipAtQuota {
+		cmd += " --skip-at-quota"
+		if opts.roleQuota != defaultRoleQuota {
+			cmd += fmt.Sprintf(" --role-quota %d", opts.roleQuota)
+		}
 	}
+	if opts.assumeYes {
+		cmd += " --yes"
+	}
+	cmd += pacingFlags(opts)
 
 	return cmd
 }
 
 // validateGitHubEnvironment validates GHES version and OAuth scopes
 func validateGitHubEnvironment(hostname string, targetingAllOrgs bool) error {
-	client, err := api.NewRESTClient(api.ClientOptions{
-		Host: hostname,
-		Headers: map[string]string{
-			"Accept":               "application/vnd.github+json",
-			"X-GitHub-Api-Version": "2022-11-28",
-		},
-	})
+	missing, err := missingScopes(hostname, targetingAllOrgs)
+	if err != nil {
+		return err
+	}
+
+	// Offer to add the missing scopes rather than sending the user off to run
+	// the command themselves. Runs that cannot prompt, such as reconcile and
+	// the hosts of a multi-host run, get the command in the error instead.
+	refreshCmd := fmt.Sprintf("gh auth refresh -h %s -s %s", hostname, strings.Join(missing, ","))
+	refreshPrompt := fmt.Sprintf("Run %s now?", refreshCmd)
+	if len(missing) > 0 && checkPrompt(refreshPrompt) == nil && term.IsTerminal(os.Stdout) {
+		pterm.Warning.Printfln("Missing required OAuth scopes: %s", strings.Join(missing, ", "))
+		refresh, err := promptConfirm(refreshPrompt)
+		if err != nil {
+			return err
+		}
+		if refresh {
+			if err := gh.ExecInteractive(context.Background(), "auth", "refresh", "-h", hostname, "-s", strings.Join(missing, ",")); err != nil {
+				return fmt.Errorf("gh auth refresh failed: %w", err)
+			}
+			// The refreshed token is only picked up by new clients
+			resetClients(hostname)
+			missing, err = missingScopes(hostname, targetingAllOrgs)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		if missing[0] == "read:enterprise" {
+			return fmt.Errorf("missing required OAuth scope 'read:enterprise' for targeting all organizations. Please run: gh auth refresh -h %s -s read:enterprise", hostname)
+		}
+		return fmt.Errorf("missing required OAuth scope '%s'. Please run: gh auth refresh -h %s -s %s", strings.Join(missing, "', '"), hostname, strings.Join(missing, ","))
+	}
+
+	if version := ghesVersion(hostname); version != "" {
+		pterm.Info.Printfln("Connected to %s (%s %s)", hostname, hostType(hostname), version)
+	} else {
+		pterm.Info.Printfln("Connected to %s (%s)", hostname, hostType(hostname))
+	}
+	return nil
+}
+
+// missingScopes returns the OAuth scopes the token lacks: admin:org for all
+// operations, and read:enterprise when targeting all organizations
+func missingScopes(hostname string, targetingAllOrgs bool) ([]string, error) {
+	client, err := restClient(hostname)
 	if err != nil {
-		return fmt.Errorf("failed to initialize GitHub API client: %w", err)
+		return nil, err
 	}
 
 	resp, err := client.Request("GET", "meta", nil)
 	if err != nil {
-		return fmt.Errorf("failed to fetch GitHub meta endpoint: %w", err)
+		return nil, fmt.Errorf("failed to fetch GitHub meta endpoint: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
 		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return fmt.Errorf("failed to fetch GitHub meta endpoint: %s (%s)", resp.Status, strings.TrimSpace(string(snippet)))
+		return nil, fmt.Errorf("failed to fetch GitHub meta endpoint: %s (%s)", resp.Status, strings.TrimSpace(string(snippet)))
 	}
 
 	oauthScopes := resp.Header.Get("X-OAuth-Scopes")
@@ -728,17 +1391,14 @@ func validateGitHubEnvironment(hostname string, targetingAllOrgs bool) error {
 	// Validate OAuth scopes
 	scopes := parseOAuthScopes(oauthScopes)
 
-	// Check for admin:org scope (required for all operations)
+	var missing []string
 	if !hasScope(scopes, "admin:org") {
-		return fmt.Errorf("missing required OAuth scope 'admin:org'. Please run: gh auth refresh -h %s -s admin:org", hostname)
+		missing = append(missing, "admin:org")
 	}
-
-	// Check for read:enterprise scope when targeting all orgs
 	if targetingAllOrgs && !hasScope(scopes, "read:enterprise") {
-		return fmt.Errorf("missing required OAuth scope 'read:enterprise' for targeting all organizations. Please run: gh auth refresh -h %s -s read:enterprise", hostname)
+		missing = append(missing, "read:enterprise")
 	}
-
-	return nil
+	return missing, nil
 }
 
 // parseOAuthScopes parses comma-separated OAuth scopes
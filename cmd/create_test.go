@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestValidateGitHubEnvironmentWithoutPrompts checks that a run that cannot
+// prompt reports the missing scopes with the command that adds them
+func TestValidateGitHubEnvironmentWithoutPrompts(t *testing.T) {
+	hostname := fakeHost(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/meta" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("X-OAuth-Scopes", "repo, read:org")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+
+	noPrompts = true
+	t.Cleanup(func() { noPrompts = false })
+
+	err := validateGitHubEnvironment(hostname, false)
+	if err == nil {
+		t.Fatal("validateGitHubEnvironment succeeded without admin:org")
+	}
+	want := "gh auth refresh -h " + hostname + " -s admin:org"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want it to name %q", err, want)
+	}
+	if strings.Contains(err.Error(), "cannot prompt") {
+		t.Errorf("error = %q, want the missing scope instead of the prompt failure", err)
+	}
+}
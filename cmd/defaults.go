@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/cli/go-gh/v2/pkg/config"
+)
+
+// promptDefaults holds answers from the previous run that are offered as
+// defaults in the interactive prompts
+type promptDefaults struct {
+	Hostname   string `json:"hostname,omitempty"`
+	Enterprise string `json:"enterprise,omitempty"`
+	BaseRole   string `json:"base_role,omitempty"`
+	TargetMode string `json:"target_mode,omitempty"`
+}
+
+// Target mode labels shown in the organization selection prompt
+const (
+	targetModeSingle = "Single organization"
+	targetModeAll    = "All organizations in enterprise"
+	targetModeCSV    = "CSV file"
+)
+
+var savedDefaults = loadPromptDefaults()
+
+func promptDefaultsPath() string {
+	return filepath.Join(config.StateDir(), "gh-custom-roles", "defaults.json")
+}
+
+// loadPromptDefaults reads the saved defaults, returning empty defaults when
+// none have been saved or the file cannot be read
+func loadPromptDefaults() promptDefaults {
+	var defaults promptDefaults
+	data, err := os.ReadFile(promptDefaultsPath())
+	if err != nil {
+		return defaults
+	}
+	_ = json.Unmarshal(data, &defaults)
+	return defaults
+}
+
+// savePromptDefaults records the answers used for this run. Failures are
+// ignored since remembered defaults are only a convenience.
+func savePromptDefaults(opts options, baseRole string) {
+	defaults := savedDefaults
+	if opts.hostname != "" {
+		defaults.Hostname = opts.hostname
+	}
+	if opts.enterprise != "" {
+		defaults.Enterprise = opts.enterprise
+	}
+	if baseRole != "" {
+		defaults.BaseRole = baseRole
+	}
+	switch {
+	case opts.org != "":
+		defaults.TargetMode = targetModeSingle
+	case opts.allOrgs:
+		defaults.TargetMode = targetModeAll
+	case len(opts.orgsCSVPaths) > 0:
+		defaults.TargetMode = targetModeCSV
+	}
+
+	data, err := json.MarshalIndent(defaults, "", "  ")
+	if err != nil {
+		return
+	}
+	path := promptDefaultsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return
+	}
+	savedDefaults = defaults
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+var deleteCmd = &cobra.Command{
+	Use:         "delete",
+	Short:       "Delete a custom repository role from one, many, or all organizations",
+	Args:        cobra.NoArgs,
+	RunE:        runDelete,
+	Annotations: mutatingCommand,
+}
+
+func init() {
+	// Delete command flags
+	deleteCmd.Flags().StringVarP(&opts.roleName, "role-name", "n", "", "Name of the custom role to delete")
+	deleteCmd.Flags().BoolVarP(&opts.assumeYes, "yes", "y", false, "Skip the confirmation prompt")
+	deleteCmd.Flags().StringVar(&opts.confirmEnterprise, "confirm-enterprise", "", "Enterprise slug, retyped to approve deleting the role with --all-orgs")
+}
+
+func runDelete(_ *cobra.Command, _ []string) error {
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	if err := selectTargets(); err != nil {
+		return err
+	}
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, opts.allOrgs); err != nil {
+		return err
+	}
+
+	if err := resolveEnterprise(); err != nil {
+		return err
+	}
+
+	orgs, err := resolveOrganizations(opts)
+	if err != nil {
+		return err
+	}
+	if len(orgs) == 0 {
+		return errors.New("no organizations provided")
+	}
+
+	if opts.roleName == "" {
+		opts.roleName, err = promptText("Custom role name to delete")
+		if err != nil {
+			return err
+		}
+	}
+	opts.roleName = strings.TrimSpace(opts.roleName)
+	if opts.roleName == "" {
+		return errors.New("role name is required")
+	}
+
+	// Validate concurrency and delay bounds
+	if err := validatePacing(opts); err != nil {
+		return err
+	}
+
+	// Display confirmation before deleting roles
+	pterm.Println()
+	pterm.DefaultSection.Println("Confirmation")
+	printAccount(opts.hostname)
+	pterm.Info.Printfln("Role Name: %s", opts.roleName)
+	pterm.Info.Printfln("Target Organizations: %d", len(orgs))
+	pterm.Warning.Println("Teams, users, and pending invitations with the role fall back to the organization's base permissions. Deleted roles cannot be restored.")
+
+	// Each organization needs a role lookup and a delete request
+	checkRateLimitBudget(opts.hostname, len(orgs)*2)
+	pterm.Println()
+
+	confirm := opts.assumeYes
+	if !confirm {
+		confirm, err = confirmRun("Begin role deletion?")
+		if err != nil {
+			return err
+		}
+	}
+	if !confirm {
+		pterm.Info.Println("Role deletion cancelled.")
+		return nil
+	}
+	if err := confirmEnterprise(opts, "delete the role "+opts.roleName); err != nil {
+		return err
+	}
+	pterm.Println()
+
+	results := newRunResults(len(orgs))
+
+	progressBar, err := startProgressbar(len(orgs), "Deleting custom roles")
+	if err != nil {
+		return err
+	}
+	defer progressBar.Stop()
+
+	results.Track(progressBar)
+
+	processQueue(opts, untilStopped(queueTargets(orgs), results.Stopped()), func(org string) {
+		existing, _, findErr := findRole(opts.hostname, org, opts.roleName)
+		switch {
+		case findErr != nil && isNotFoundError(findErr):
+			results.Skipped(org, "Organization not found", "Organization %s not found. Skipping.", org)
+		case isPlanUnsupportedError(findErr):
+			results.SkippedAs(categoryPlanUnsupported, org, "Custom roles not available on this plan", "Organization %s cannot use custom roles: %s. Skipping.", org, planRequirement)
+		case findErr != nil:
+			results.Failed(org, findErr.Error(), "Failed to check existing roles for %s: %v", org, findErr)
+		case existing == nil:
+			results.Skipped(org, "Role not found", "Organization %s has no role named %s. Skipping.", org, opts.roleName)
+		case excludedByManagedOnly(*existing):
+			results.SkippedAs(categoryUnmanaged, org, "Role not managed by gh-custom-roles", "Role %s in %s is not managed by gh-custom-roles. Skipping (--managed-only).", existing.Name, org)
+		default:
+			deleteErr := deleteCustomRole(opts.hostname, org, existing.ID)
+			switch {
+			case deleteErr != nil && isNotFoundError(deleteErr):
+				// Deleted by someone else since the lookup
+				results.Skipped(org, "Role not found", "Organization %s has no role named %s. Skipping.", org, opts.roleName)
+			case deleteErr != nil:
+				results.Failed(org, deleteErr.Error(), "Failed to delete role %s in %s: %v", existing.Name, org, deleteErr)
+			default:
+				results.Succeeded(org, "Role deleted", "Deleted role %s in %s", existing.Name, org)
+			}
+		}
+	})
+
+	progressBar.Stop()
+
+	// Display summary
+	pterm.Println()
+	pterm.DefaultSection.Println("Summary")
+	results.printSummaryCounts("deleted")
+
+	// Display command for replication
+	cmd := buildDeleteReplicationCommand(opts)
+	printReplicationTip("these changes", cmd)
+
+	// Publish the run summary (GitHub Actions step summary, tracking issue)
+	details := []summaryDetail{
+		{Label: "Role Name", Value: opts.roleName},
+		{Label: "Target Organizations", Value: fmt.Sprintf("%d", len(orgs))},
+		{Label: "Replication Command", Value: "`" + cmd + "`"},
+	}
+	publishRunSummary(opts, "Custom role deletion: "+opts.roleName, details, results)
+
+	return results.Err()
+}
+
+// deleteCustomRole deletes a custom role from an organization
+func deleteCustomRole(hostname, org string, roleID int64) error {
+	_, stderr, err := ghAPI(hostname, "-X", "DELETE", "orgs/"+org+"/custom-repository-roles/"+strconv.FormatInt(roleID, 10))
+	if err != nil {
+		return fmt.Errorf("delete role failed: %w (%s)", err, stderr.String())
+	}
+	return nil
+}
+
+func buildDeleteReplicationCommand(opts options) string {
+	cmd := "gh custom-roles delete"
+
+	if opts.hostname != "" {
+		cmd += " --hostname " + shellQuote(opts.hostname)
+	}
+	cmd += targetFlags(opts)
+	cmd += " --role-name " + shellQuote(opts.roleName)
+	if opts.assumeYes {
+		cmd += " --yes"
+	}
+	cmd += pacingFlags(opts)
+
+	return cmd
+}
@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// roleChange is one field of a custom role that an edit changes
+type roleChange struct {
+	Field   string
+	Current string
+	New     string
+}
+
+var editCmd = &cobra.Command{
+	Use:         "edit",
+	Short:       "Edit an existing custom repository role in an organization",
+	RunE:        runEdit,
+	Annotations: mutatingCommand,
+}
+
+func init() {
+	// Edit command flags
+	editCmd.Flags().StringVarP(&opts.roleName, "role-name", "n", "", "Name of the custom role to edit")
+	editCmd.Flags().StringVar(&opts.newRoleName, "new-name", "", "Rename the role")
+	editCmd.Flags().StringVarP(&opts.roleDesc, "role-description", "d", "", "New role description (pass an empty value to clear it)")
+	editCmd.Flags().StringVarP(&opts.baseRole, "base-role", "b", "", "New base role (read, triage, write, maintain)")
+	editCmd.Flags().StringVarP(&opts.permissions, "permissions", "p", "", "Comma-separated list of permission names, replacing the current ones")
+}
+
+func runEdit(cmd *cobra.Command, _ []string) error {
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	if opts.allOrgs || len(opts.orgsCSVPaths) > 0 || opts.orgsFromCmd != "" {
+		return errors.New("edit changes a role in a single organization; use --org")
+	}
+	if opts.org == "" {
+		opts.org, err = promptText("Organization name")
+		if err != nil {
+			return err
+		}
+	}
+	org := normalizeOrg(opts.org)
+	if org == "" {
+		return errors.New("organization name is required")
+	}
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, false); err != nil {
+		return err
+	}
+
+	roles, err := listCustomRoles(opts.hostname, org)
+	if err != nil {
+		return err
+	}
+	if len(roles) == 0 {
+		return fmt.Errorf("organization %s has no custom roles", org)
+	}
+	if opts.roleName == "" {
+		names := make([]string, 0, len(roles))
+		for _, role := range roles {
+			names = append(names, role.Name)
+		}
+		opts.roleName, err = promptSelect("Select role to edit", names, "")
+		if err != nil {
+			return err
+		}
+	}
+	var current customRole
+	found := false
+	for _, role := range roles {
+		if sameRoleName(role.Name, opts.roleName) {
+			current, found = role, true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("organization %s has no role named %s", org, opts.roleName)
+	}
+	if excludedByManagedOnly(current) {
+		return fmt.Errorf("role %s in %s is not managed by gh-custom-roles; drop --managed-only to edit it", current.Name, org)
+	}
+	opts.roleName = current.Name
+
+	// Every field defaults to the current definition. Interactively, pressing
+	// enter keeps a field; once any field is given as a flag, the others are
+	// kept without prompting.
+	interactive := true
+	for _, name := range []string{"new-name", "role-description", "base-role", "permissions"} {
+		if cmd.Flags().Changed(name) {
+			interactive = false
+		}
+	}
+
+	newName := strings.TrimSpace(opts.newRoleName)
+	if interactive {
+		newName, err = promptText(fmt.Sprintf("Role name (press enter to keep %s)", current.Name))
+		if err != nil {
+			return err
+		}
+		newName = strings.TrimSpace(newName)
+	}
+	if newName == "" {
+		newName = current.Name
+	}
+
+	// Only a new description is trimmed, so a stored one with surrounding
+	// whitespace is kept as is and not reported as a change
+	description := current.Description
+	if cmd.Flags().Changed("role-description") {
+		description = strings.TrimSpace(opts.roleDesc)
+	} else if interactive {
+		if current.Description != "" {
+			pterm.Info.Printfln("Current description: %s", current.Description)
+		}
+		answer, err := promptText("Role description (press enter to keep the current description)")
+		if err != nil {
+			return err
+		}
+		if answer = strings.TrimSpace(answer); answer != "" {
+			description = answer
+		}
+	}
+	if isManaged(current) && description != current.Description {
+		// A new description keeps the role marked as managed
+		description = markManaged(description)
+	}
+
+	baseRole := current.BaseRole
+	if opts.baseRole != "" {
+		baseRole, err = resolveBaseRole(opts.baseRole)
+	} else if interactive {
+		baseRole, err = promptSelect("Select base role", []string{"read", "triage", "write", "maintain"}, current.BaseRole)
+	}
+	if err != nil {
+		return err
+	}
+
+	permissions := current.Permissions
+	if opts.permissions != "" || interactive {
+		catalog, err := listFineGrainedPermissions(opts.hostname, org)
+		if err != nil {
+			return err
+		}
+		if opts.permissions != "" {
+			permissions, err = resolvePermissions(opts.permissions, catalog)
+		} else {
+			permissions, err = selectPermissions(catalog, current.Permissions)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	changes := roleChanges(current, newName, description, baseRole, permissions)
+	if len(changes) == 0 {
+		pterm.Info.Printfln("No changes to %s in %s.", current.Name, org)
+		return nil
+	}
+	if blocked := blockedPrivilegeIncrease(current, baseRole, permissions); blocked != "" {
+		return fmt.Errorf("editing role %s in %s would increase its privileges (%s); pass --allow-privilege-increase to apply it", current.Name, org, blocked)
+	}
+	increase := privilegeIncrease(current, baseRole, permissions)
+
+	// Display confirmation before editing the role
+	pterm.Println()
+	pterm.DefaultSection.Println("Confirmation")
+	printAccount(opts.hostname)
+	pterm.Info.Printfln("Organization: %s", org)
+	pterm.Info.Printfln("Role: %s", current.Name)
+	if increase != "" {
+		pterm.Warning.Printfln("This edit increases the role's privileges: %s", increase)
+	}
+	data := pterm.TableData{{"Field", "Current", "New"}}
+	for _, change := range changes {
+		data = append(data, []string{change.Field, change.Current, change.New})
+	}
+	if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+		return err
+	}
+	pterm.Println()
+
+	confirm, err := confirmRun("Apply these changes?")
+	if err != nil {
+		return err
+	}
+	if !confirm {
+		pterm.Info.Println("Role edit cancelled.")
+		return nil
+	}
+	pterm.Println()
+
+	results := newRunResults(1)
+	updateErr := updateCustomRole(opts.hostname, org, current.ID, changes, permissions)
+	category, validationMessage, invalid := validationFailure(updateErr)
+	switch {
+	case invalid:
+		results.FailedAs(category, org, validationMessage, "Failed to edit role %s in %s: %s", current.Name, org, validationMessage)
+	case updateErr != nil:
+		results.Failed(org, updateErr.Error(), "Failed to edit role %s in %s: %v", current.Name, org, updateErr)
+	default:
+		results.RecordUpdate(org, current, changes, permissions)
+		results.Succeeded(org, fmt.Sprintf("Changed %d fields", len(changes)), "Updated role %s in %s", newName, org)
+	}
+
+	// Display summary
+	pterm.Println()
+	pterm.DefaultSection.Println("Summary")
+	results.printSummaryCounts("edited")
+
+	// Display command for replication
+	replication := buildEditReplicationCommand(opts, org, current.Name, changes, permissions)
+	printReplicationTip("this edit", replication)
+
+	// Publish the run summary (GitHub Actions step summary, tracking issue)
+	details := []summaryDetail{
+		{Label: "Organization", Value: org},
+		{Label: "Role Name", Value: current.Name},
+	}
+	for _, change := range changes {
+		details = append(details, summaryDetail{Label: change.Field, Value: change.Current + " → " + change.New})
+	}
+	details = append(details, summaryDetail{Label: "Replication Command", Value: "`" + replication + "`"})
+	publishRunSummary(opts, "Custom role edit: "+current.Name, details, results)
+
+	return results.Err()
+}
+
+// roleChanges lists the fields that differ between a role and its edited
+// definition. Permissions are compared as sets.
+func roleChanges(current customRole, name, description, baseRole string, permissions []string) []roleChange {
+	var changes []roleChange
+	if name != current.Name {
+		changes = append(changes, roleChange{Field: "Name", Current: current.Name, New: name})
+	}
+	if description != current.Description {
+		changes = append(changes, roleChange{Field: "Description", Current: current.Description, New: description})
+	}
+	if baseRole != current.BaseRole {
+		changes = append(changes, roleChange{Field: "Base Role", Current: current.BaseRole, New: baseRole})
+	}
+
+	currentSet := map[string]bool{}
+	for _, permission := range current.Permissions {
+		currentSet[permission] = true
+	}
+	newSet := map[string]bool{}
+	var added []string
+	for _, permission := range permissions {
+		newSet[permission] = true
+		if !currentSet[permission] {
+			added = append(added, "+"+permission)
+		}
+	}
+	var removed []string
+	for _, permission := range current.Permissions {
+		if !newSet[permission] {
+			removed = append(removed, "-"+permission)
+		}
+	}
+	if len(added) > 0 || len(removed) > 0 {
+		changes = append(changes, roleChange{
+			Field:   "Permissions",
+			Current: strings.Join(current.Permissions, ", "),
+			New:     strings.Join(append(added, removed...), ", "),
+		})
+	}
+	return changes
+}
+
+// updateCustomRole sends only the changed fields of a role
+func updateCustomRole(hostname, org string, roleID int64, changes []roleChange, permissions []string) error {
+	args := []string{"-X", "PATCH", "orgs/" + org + "/custom-repository-roles/" + strconv.FormatInt(roleID, 10)}
+	for _, change := range changes {
+		switch change.Field {
+		case "Name":
+			args = append(args, "-f", "name="+change.New)
+		case "Description":
+			args = append(args, "-f", "description="+change.New)
+		case "Base Role":
+			args = append(args, "-f", "base_role="+change.New)
+		case "Permissions":
+			for _, permission := range permissions {
+				args = append(args, "-f", "permissions[]="+permission)
+			}
+		}
+	}
+	_, stderr, err := ghAPI(hostname, args...)
+	if err != nil {
+		return fmt.Errorf("update role failed: %w (%s)", err, stderr.String())
+	}
+	return nil
+}
+
+func buildEditReplicationCommand(opts options, org, roleName string, changes []roleChange, permissions []string) string {
+	cmd := "gh custom-roles edit"
+
+	if opts.hostname != "" {
+		cmd += " --hostname " + shellQuote(opts.hostname)
+	}
+	cmd += " --org " + shellQuote(org)
+	cmd += " --role-name " + shellQuote(roleName)
+	for _, change := range changes {
+		switch change.Field {
+		case "Name":
+			cmd += " --new-name " + shellQuote(change.New)
+		case "Description":
+			cmd += " --role-description " + shellQuote(change.New)
+		case "Base Role":
+			cmd += " --base-role " + shellQuote(change.New)
+		case "Permissions":
+			cmd += " --permissions " + shellQuote(strings.Join(permissions, ","))
+		}
+	}
+	if opts.managedOnly {
+		cmd += " --managed-only"
+	}
+	cmd += privilegeGuardFlags(opts)
+
+	return cmd
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/config"
+)
+
+// resolveEditor returns the editor command using the same precedence as gh:
+// GH_EDITOR, the gh editor setting, VISUAL, EDITOR, then a platform default
+func resolveEditor() string {
+	if editor := os.Getenv("GH_EDITOR"); editor != "" {
+		return editor
+	}
+	if cfg, err := config.Read(nil); err == nil {
+		if editor, err := cfg.Get([]string{"editor"}); err == nil && editor != "" {
+			return editor
+		}
+	}
+	if editor := os.Getenv("VISUAL"); editor != "" {
+		return editor
+	}
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "nano"
+}
+
+// editText opens initial in the user's editor and returns the edited text
+// with comment lines (starting with '#') removed
+func editText(initial string) (string, error) {
+	file, err := os.CreateTemp("", "gh-custom-roles-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(initial); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	editorArgs := strings.Fields(resolveEditor())
+	editorCmd := exec.Command(editorArgs[0], append(editorArgs[1:], file.Name())...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %s failed: %w", editorArgs[0], err)
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, "\r"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), nil
+}
+
+// editDescription opens the role description in the user's editor
+func editDescription(roleName, current string) (string, error) {
+	template := current + "\n\n" +
+		"# Enter the description for the " + roleName + " role above.\n" +
+		"# Lines starting with '#' are ignored.\n"
+	return editText(template)
+}
+
+// editPermissions opens the permission catalog in the user's editor and
+// returns a comma-separated list of the lines left uncommented
+func editPermissions(permissions []fineGrainedPermission, current []string) (string, error) {
+	selected := map[string]bool{}
+	for _, name := range current {
+		selected[name] = true
+	}
+
+	sort.SliceStable(permissions, func(i, j int) bool {
+		return permissions[i].Name < permissions[j].Name
+	})
+
+	var builder strings.Builder
+	builder.WriteString("# Uncomment the permissions to grant, one per line.\n")
+	builder.WriteString("# Lines starting with '#' are ignored.\n\n")
+	for _, perm := range permissions {
+		line := perm.Name
+		if perm.Description != "" {
+			line += " # " + perm.Description
+		}
+		if !selected[perm.Name] {
+			line = "# " + line
+		}
+		builder.WriteString(line + "\n")
+	}
+
+	text, err := editText(builder.String())
+	if err != nil {
+		return "", err
+	}
+
+	var names []string
+	for _, line := range strings.Split(text, "\n") {
+		// Drop trailing descriptions kept on uncommented lines
+		name, _, _ := strings.Cut(line, "#")
+		name = strings.TrimSpace(name)
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ","), nil
+}
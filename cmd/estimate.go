@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pterm/pterm"
+)
+
+// GitHub.com and GHE.com allow at most this many content-creating requests,
+// such as role creations and updates, per minute and per hour. A server's
+// secondary limits are set by its administrators.
+const (
+	contentWritesPerMinute = 80
+	contentWritesPerHour   = 500
+)
+
+// apiCost is the number of requests one step of a run is expected to make.
+// Writes are content-creating requests, which count towards the secondary
+// rate limits.
+type apiCost struct {
+	Step     string
+	Requests int
+	GraphQL  bool
+	Write    bool
+	// UpTo marks an upper bound, for steps that stop early on success
+	UpTo bool
+}
+
+// estimateCreateCost returns the requests a create run still makes after
+// --preview checked every organization: creations, --force updates of
+// outdated roles, the audit log search, and the run summary reports
+func estimateCreateCost(opts options, check roleCheck, baseRole string, permissions []string) []apiCost {
+	updates := 0
+	if opts.force {
+		for _, existing := range check.Existing {
+			if !excludedByManagedOnly(existing) && len(roleChanges(existing, existing.Name, opts.roleDesc, baseRole, permissions)) > 0 && blockedPrivilegeIncrease(existing, baseRole, permissions) == "" {
+				updates++
+			}
+		}
+	}
+
+	costs := []apiCost{
+		{Step: "Create role", Requests: len(check.Create), Write: true},
+		{Step: "Update outdated role (--force)", Requests: updates, Write: true},
+	}
+	if opts.verifyAuditLog && len(check.Create) > 0 {
+		// One search per attempt, of the enterprise or of every organization
+		searches := len(check.Create)
+		if opts.enterprise != "" {
+			searches = 1
+		}
+		costs = append(costs, apiCost{Step: "Audit log search (--verify-audit-log)", Requests: searches * auditLogAttempts, UpTo: true})
+	}
+	if opts.reportIssue != "" {
+		costs = append(costs, apiCost{Step: "Run summary comment (--report-issue)", Requests: 1, Write: true})
+	}
+	if opts.reportGist {
+		costs = append(costs, apiCost{Step: "Run summary gist (--report-gist)", Requests: 1, Write: true})
+	}
+	return costs
+}
+
+// printCostEstimate shows the requests a run is expected to make, compares
+// them with the remaining rate limit budget, and suggests pacing that keeps
+// the run within the limits
+func printCostEstimate(opts options, costs []apiCost) error {
+	pterm.DefaultSection.Println("API cost estimate")
+
+	var rest, graphQL, writes int
+	data := pterm.TableData{{"Step", "Requests", "API"}}
+	for _, cost := range costs {
+		if cost.Requests == 0 {
+			continue
+		}
+		api := "REST"
+		if cost.GraphQL {
+			api = "GraphQL"
+			graphQL += cost.Requests
+		} else {
+			rest += cost.Requests
+		}
+		if cost.Write {
+			writes += cost.Requests
+		}
+		requests := fmt.Sprintf("%d", cost.Requests)
+		if cost.UpTo {
+			requests = "up to " + requests
+		}
+		data = append(data, []string{cost.Step, requests, api})
+	}
+	if len(data) > 1 {
+		if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+			return err
+		}
+	}
+	pterm.Info.Printfln("Requests made so far: %s", apiUsage.Snapshot())
+	pterm.Info.Printfln("Estimated requests to finish the run: %d REST, %d GraphQL", rest, graphQL)
+	if rest+graphQL == 0 {
+		return nil
+	}
+
+	limits, err := fetchRateLimits(opts.hostname)
+	switch {
+	case err != nil && isNotFoundError(err):
+		// GHES returns 404 when rate limiting is disabled on the instance
+		pterm.Info.Println("API Rate Limit: not enforced on this host")
+	case err != nil:
+		pterm.Warning.Printfln("Unable to check API rate limits: %v", err)
+	default:
+		core := limits.Resources.Core
+		pterm.Info.Printfln("REST API Budget: %d of %d remaining (resets %s)", core.Remaining, core.Limit, formatReset(core.Reset))
+		if rest > core.Remaining {
+			shards := int(math.Ceil(float64(rest) / float64(max(core.Remaining, 1))))
+			pterm.Warning.Printfln("The run needs %d REST requests but only %d remain. Wait for the reset %s, or split it with --shard 1/%d through --shard %d/%d in separate rate limit windows.", rest, core.Remaining, formatReset(core.Reset), shards, shards, shards)
+		}
+		if graphQL > 0 {
+			graphql := limits.Resources.GraphQL
+			pterm.Info.Printfln("GraphQL API Budget: %d of %d remaining (resets %s)", graphql.Remaining, graphql.Limit, formatReset(graphql.Reset))
+			if graphQL > graphql.Remaining {
+				pterm.Warning.Printfln("The run needs %d GraphQL requests but only %d remain. Wait for the reset %s.", graphQL, graphql.Remaining, formatReset(graphql.Reset))
+			}
+		}
+	}
+
+	printPacingAdvice(opts, writes)
+	return nil
+}
+
+// printPacingAdvice compares the write rate of the current --concurrency,
+// --delay, and --requests-per-second with the secondary rate limits for
+// content-creating requests, and suggests settings that stay below them
+func printPacingAdvice(opts options, writes int) {
+	if writes == 0 {
+		return
+	}
+	perMinute := writesPerMinute(opts)
+	if perMinute > 0 {
+		pterm.Info.Printfln("Shortest duration at the current pacing: %s (up to %.0f writes per minute)", formatMinutes(float64(writes)/perMinute), perMinute)
+	}
+	if hostType(opts.hostname) == hostTypeServer {
+		return
+	}
+
+	paced := true
+	if writes > contentWritesPerMinute && (perMinute == 0 || perMinute > contentWritesPerMinute) {
+		paced = false
+		pterm.Warning.Printfln("The current pacing can exceed %d content-creating requests per minute, GitHub's secondary rate limit. Use --requests-per-second 1 or --delay 1 (or --profile-pace gentle) to stay below it.", contentWritesPerMinute)
+	}
+	if writes > contentWritesPerHour && (perMinute == 0 || perMinute*60 > contentWritesPerHour) {
+		paced = false
+		delay := int(math.Ceil(3600.0 / contentWritesPerHour))
+		pterm.Warning.Printfln("The run makes %d writes, more than the %d content-creating requests GitHub allows per hour. Use --delay %d, or split it with --shard into runs an hour apart.", writes, contentWritesPerHour, delay)
+	}
+	if paced {
+		pterm.Success.Println("The current pacing stays within GitHub's secondary rate limits for writes")
+	}
+}
+
+// writesPerMinute returns the most requests per minute the current pacing
+// allows, or 0 when only response times limit it
+func writesPerMinute(opts options) float64 {
+	var perMinute float64
+	if opts.delay > 0 {
+		// Targets are processed sequentially with the delay between them
+		perMinute = 60 / float64(opts.delay)
+	}
+	if opts.requestRate > 0 && (perMinute == 0 || opts.requestRate*60 < perMinute) {
+		perMinute = opts.requestRate * 60
+	}
+	return perMinute
+}
+
+// formatMinutes describes a number of minutes, such as "about 12 minutes"
+func formatMinutes(minutes float64) string {
+	if minutes < 1 {
+		return "under a minute"
+	}
+	return "about " + pluralize(int(math.Ceil(minutes)), "minute", "minutes")
+}
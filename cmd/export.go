@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats for exported data
+const (
+	formatTable    = "table"
+	formatCSV      = "csv"
+	formatJSON     = "json"
+	formatYAML     = "yaml"
+	formatMarkdown = "markdown"
+)
+
+// Grant sources: how a grantee came to hold a role on a repository
+const (
+	sourceDirect = "direct"
+	sourceTeam   = "team"
+)
+
+// assignmentRecord is one exported assignment row, shaped for access-review
+// evidence
+type assignmentRecord struct {
+	// Host is set in multi-host runs
+	Host        string `json:"host,omitempty" yaml:"host,omitempty"`
+	Org         string `json:"org" yaml:"org"`
+	Repo        string `json:"repo" yaml:"repo"`
+	GranteeType string `json:"grantee_type" yaml:"grantee_type"`
+	Grantee     string `json:"grantee" yaml:"grantee"`
+	Role        string `json:"role" yaml:"role"`
+	RoleKind    string `json:"role_kind" yaml:"role_kind"`
+	Source      string `json:"source" yaml:"source"`
+}
+
+// validateOutputFormat checks --format and, when machine-readable output goes
+// to stdout, moves all other output to stderr so it can be piped
+func validateOutputFormat(opts options) error {
+	switch opts.outputFormat {
+	case formatTable:
+		if opts.outputPath != "" {
+			return fmt.Errorf("--output requires --format %s, %s, %s, or %s", formatCSV, formatJSON, formatYAML, formatMarkdown)
+		}
+	case formatCSV, formatJSON, formatYAML, formatMarkdown:
+		if opts.outputPath == "" {
+			pterm.SetDefaultOutput(os.Stderr)
+		}
+	default:
+		return fmt.Errorf("invalid format %q: expected %s, %s, %s, %s, or %s", opts.outputFormat, formatTable, formatCSV, formatJSON, formatYAML, formatMarkdown)
+	}
+	return nil
+}
+
+func newAssignmentRecord(grant repoGrant) assignmentRecord {
+	source := sourceDirect
+	if grant.GranteeType == "team" {
+		source = sourceTeam
+	}
+	record := assignmentRecord{
+		Org:         grant.Org,
+		Repo:        grant.Repo,
+		GranteeType: grant.GranteeType,
+		Grantee:     grant.Grantee,
+		Role:        grant.Role,
+		RoleKind:    roleKind(grant.Role),
+		Source:      source,
+	}
+	if hostColumns() {
+		record.Host = opts.hostname
+	}
+	return record
+}
+
+// exportAssignments writes grants in opts.outputFormat to opts.outputPath, or
+// to stdout when no path is given
+func exportAssignments(opts options, grants []repoGrant) error {
+	records := make([]assignmentRecord, 0, len(grants))
+	for _, grant := range grants {
+		records = append(records, newAssignmentRecord(grant))
+	}
+
+	var out io.Writer = os.Stdout
+	if opts.outputPath != "" {
+		file, err := os.Create(filepath.Clean(opts.outputPath))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	var err error
+	switch opts.outputFormat {
+	case formatJSON:
+		err = writeJSON(out, records)
+	case formatYAML:
+		err = writeYAML(out, records)
+	case formatMarkdown:
+		err = writeAssignmentsMarkdown(out, records)
+	default:
+		err = writeAssignmentsCSV(out, records)
+	}
+	if err != nil {
+		return err
+	}
+	if file, ok := out.(*os.File); ok && file != os.Stdout {
+		return file.Close()
+	}
+	return nil
+}
+
+func writeAssignmentsCSV(out io.Writer, records []assignmentRecord) error {
+	writer := csv.NewWriter(out)
+	_ = writer.Write(withHostColumn("host", []string{"org", "repo", "grantee_type", "grantee", "role", "role_kind", "source"}))
+	for _, record := range records {
+		_ = writer.Write(withHostColumn(record.Host, []string{record.Org, record.Repo, record.GranteeType, record.Grantee, record.Role, record.RoleKind, record.Source}))
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeJSON(out io.Writer, value any) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// writeYAML writes value as YAML. Keys follow the struct field order so
+// repeated exports diff cleanly.
+func writeYAML(out io.Writer, value any) error {
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// validateStructuredFormat checks --format for commands that print a table
+// or write json or yaml, and moves all other output to stderr when the data
+// goes to stdout
+func validateStructuredFormat(opts options) error {
+	switch opts.outputFormat {
+	case formatTable:
+		if opts.outputPath != "" {
+			return fmt.Errorf("--output requires --format %s or %s", formatJSON, formatYAML)
+		}
+	case formatJSON, formatYAML:
+		if opts.outputPath == "" {
+			pterm.SetDefaultOutput(os.Stderr)
+		}
+	default:
+		return fmt.Errorf("invalid format %q: expected %s, %s, or %s", opts.outputFormat, formatTable, formatJSON, formatYAML)
+	}
+	return nil
+}
+
+// exportStructured writes value in opts.outputFormat, json or yaml, to
+// opts.outputPath, or to stdout when no path is given
+func exportStructured(opts options, value any) error {
+	var out io.Writer = os.Stdout
+	if opts.outputPath != "" {
+		file, err := os.Create(filepath.Clean(opts.outputPath))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	var err error
+	if opts.outputFormat == formatYAML {
+		err = writeYAML(out, value)
+	} else {
+		err = writeJSON(out, value)
+	}
+	if err != nil {
+		return err
+	}
+	if file, ok := out.(*os.File); ok && file != os.Stdout {
+		return file.Close()
+	}
+	return nil
+}
+
+// writeAssignmentsMarkdown writes records as a GitHub-flavored markdown table
+// that can be pasted into issues, pull requests, and wikis
+func writeAssignmentsMarkdown(out io.Writer, records []assignmentRecord) error {
+	var b strings.Builder
+	header := withHostColumn("Host", []string{"Organization", "Repository", "Type", "Grantee", "Role", "Kind", "Source"})
+	b.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	b.WriteString(strings.Repeat("|---", len(header)) + "|\n")
+	for _, record := range records {
+		cells := withHostColumn(record.Host, []string{record.Org, record.Repo, record.GranteeType, record.Grantee, record.Role, record.RoleKind, record.Source})
+		for i, cell := range cells {
+			cells[i] = escapeMarkdown(cell)
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// withHostColumn puts host in front of a CSV or markdown row in multi-host
+// runs, and returns the row unchanged otherwise
+func withHostColumn(host string, row []string) []string {
+	if !hostColumns() {
+		return row
+	}
+	return append([]string{host}, row...)
+}
+
+// formatFlags returns the replication command flags for non-default output
+func formatFlags(opts options) string {
+	var flags string
+	if opts.outputFormat != "" && opts.outputFormat != formatTable {
+		flags += " --format " + opts.outputFormat
+	}
+	if opts.outputPath != "" {
+		flags += " --output " + shellQuote(opts.outputPath)
+	}
+	return flags
+}
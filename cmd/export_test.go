@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestExportStructuredRoleDefinition checks that view's json and yaml output
+// can be read back by create --from-file
+func TestExportStructuredRoleDefinition(t *testing.T) {
+	definition := roleFile{
+		Name:        "Security Reviewer",
+		Description: "Reviews code scanning alerts",
+		BaseRole:    "read",
+		Permissions: []string{"delete_alerts_code_scanning", "view_secret_scanning_alerts"},
+	}
+	for _, format := range []string{formatJSON, formatYAML} {
+		t.Run(format, func(t *testing.T) {
+			exportOpts := options{outputFormat: format, outputPath: filepath.Join(t.TempDir(), "role."+format)}
+			if err := exportStructured(exportOpts, definition); err != nil {
+				t.Fatalf("exportStructured: %v", err)
+			}
+			loaded, err := loadRoleFile(exportOpts.outputPath)
+			if err != nil {
+				t.Fatalf("loadRoleFile: %v", err)
+			}
+			if loaded.Name != definition.Name || loaded.Description != definition.Description || loaded.BaseRole != definition.BaseRole || !slices.Equal(loaded.Permissions, definition.Permissions) {
+				t.Errorf("read back %+v, want %+v", loaded, definition)
+			}
+		})
+	}
+}
+
+func TestAuditRecords(t *testing.T) {
+	today := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	due := []expiringRole{
+		{Definition: roleFile{Name: "Contractor", Annotations: roleAnnotations{Owner: "@acme/security"}}, Annotation: "expires", Due: today.AddDate(0, 0, -3), Orgs: []string{"acme-web"}},
+		{Definition: roleFile{Name: "Reviewer"}, Annotation: "review_date", Due: today.AddDate(0, 0, 5), Orgs: []string{"acme-web", "acme-data"}},
+		{Definition: roleFile{Name: "Unused"}, Annotation: "expires", Due: today},
+	}
+	records := auditRecords(due, today)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2 (roles no organization defines are left out): %+v", len(records), records)
+	}
+	if records[0].Status != auditStatusExpired || records[0].Days != -3 || records[0].Date != "2026-10-14" || records[0].Owner != "@acme/security" {
+		t.Errorf("expired record = %+v", records[0])
+	}
+	if records[1].Status != auditStatusExpiring || records[1].Days != 5 || !slices.Equal(records[1].Orgs, []string{"acme-web", "acme-data"}) {
+		t.Errorf("expiring record = %+v", records[1])
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/pterm/pterm"
+)
+
+// graphQLPartialAttempts and graphQLPartialRetryInterval bound the retries
+// of a page that came back with partial errors. Errors such as SAML
+// enforcement persist, while timeouts on a single field often clear.
+const (
+	graphQLPartialAttempts      = 3
+	graphQLPartialRetryInterval = 5 * time.Second
+)
+
+// orgProblem is a GraphQL error that affected one organization of the
+// enterprise organization listing
+type orgProblem struct {
+	// Index is the organization's position on its page, and Org its login,
+	// which is empty when the whole organization could not be read
+	Index   int
+	Org     string
+	Field   string
+	Message string
+}
+
+// String describes the problem for warnings, such as
+// "acme-sso (repositories): Resource protected by organization SAML enforcement"
+func (p orgProblem) String() string {
+	org := p.Org
+	if org == "" {
+		org = fmt.Sprintf("organization %d on the page", p.Index+1)
+	}
+	if p.Field != "" {
+		org += " (" + p.Field + ")"
+	}
+	return org + ": " + p.Message
+}
+
+// orgProblems maps the errors of a partial enterprise organization response
+// to the organizations they affected. logins holds the login of every node
+// on the page, empty for nodes that came back null. Errors that are not tied
+// to an organization node, for example on pageInfo, are returned as general.
+func orgProblems(gqlErr *api.GraphQLError, logins []string) (problems []orgProblem, general []string) {
+	for _, item := range gqlErr.Errors {
+		index, field, ok := orgNodePath(item.Path)
+		if !ok {
+			general = append(general, graphQLErrorText(item))
+			continue
+		}
+		problem := orgProblem{Index: index, Field: field, Message: item.Message}
+		if index < len(logins) {
+			problem.Org = logins[index]
+		}
+		problems = append(problems, problem)
+	}
+	return problems, general
+}
+
+// orgNodePath finds the organization node an error path points into, such
+// as ["enterprise", "organizations", "nodes", 3, "repositories"], and returns
+// the node index and the field below it
+func orgNodePath(path []interface{}) (int, string, bool) {
+	for i, element := range path {
+		if element != "nodes" || i+1 >= len(path) {
+			continue
+		}
+		index, ok := path[i+1].(float64)
+		if !ok {
+			return 0, "", false
+		}
+		var fields []string
+		for _, rest := range path[i+2:] {
+			switch value := rest.(type) {
+			case string:
+				fields = append(fields, value)
+			case float64:
+				fields = append(fields, strconv.Itoa(int(value)))
+			}
+		}
+		return int(index), strings.Join(fields, "."), true
+	}
+	return 0, "", false
+}
+
+// graphQLErrorText formats an error with its path, when it has one
+func graphQLErrorText(item api.GraphQLErrorItem) string {
+	if len(item.Path) == 0 {
+		return item.Message
+	}
+	parts := make([]string, 0, len(item.Path))
+	for _, element := range item.Path {
+		parts = append(parts, fmt.Sprint(element))
+	}
+	return strings.Join(parts, ".") + ": " + item.Message
+}
+
+// reportOrgProblems warns about the organizations a partial response left
+// out or could not filter, so the listing is never silently short
+func reportOrgProblems(enterprise string, problems []orgProblem) {
+	pterm.Warning.Printfln("The organization listing for enterprise '%s' returned %s:", enterprise, pluralize(len(problems), "error", "errors"))
+	saml := false
+	for _, problem := range problems {
+		pterm.Warning.Printfln("  %s", redactSecrets(problem.String()))
+		saml = saml || strings.Contains(problem.Message, "SAML")
+	}
+	if saml {
+		pterm.Info.Println("Organizations that enforce SAML single sign-on need a token authorized for them (gh auth refresh, then authorize the token for the organization).")
+	}
+}
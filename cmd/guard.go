@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// readOnlyEnv locks the extension to reporting commands when set to a true
+// value, for shared automation accounts that must never change roles or grants
+const readOnlyEnv = "GH_CUSTOM_ROLES_READ_ONLY"
+
+// mutatingCommand annotates commands that create roles or change grants
+var mutatingCommand = map[string]string{"mutating": "true"}
+
+// readOnly reports whether read-only mode is enabled
+func readOnly() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(readOnlyEnv))) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
+// checkReadOnly refuses to run a mutating command in read-only mode
+func checkReadOnly(cmd *cobra.Command) error {
+	if readOnly() && cmd.Annotations["mutating"] == "true" {
+		return fmt.Errorf("%s is disabled because %s is set; only reporting commands can run", cmd.Name(), readOnlyEnv)
+	}
+	return nil
+}
+
+// confirmEnterprise guards operations that change existing roles or grants
+// in every organization of an enterprise. The enterprise slug must be typed
+// again, or passed with --confirm-enterprise when running non-interactively,
+// so a command copied from another enterprise cannot run against the wrong
+// one.
+func confirmEnterprise(opts options, action string) error {
+	if !opts.allOrgs {
+		return nil
+	}
+
+	confirmation := opts.confirmEnterprise
+	if confirmation == "" {
+		var err error
+		confirmation, err = promptText(fmt.Sprintf("This will %s in every organization of %s. Type the enterprise slug to confirm", action, opts.enterprise))
+		if err != nil {
+			return err
+		}
+	}
+	if !strings.EqualFold(strings.TrimSpace(confirmation), opts.enterprise) {
+		return fmt.Errorf("enterprise confirmation %q does not match %q", strings.TrimSpace(confirmation), opts.enterprise)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/config"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// runHistoryLimit is how many past runs are kept; older records are removed
+// when a new run is saved
+const runHistoryLimit = 200
+
+// runRecord is a saved run: its parameters, exact counts, and the retained
+// per-target results
+type runRecord struct {
+	ID                string           `json:"id"`
+	Title             string           `json:"title"`
+	Hostname          string           `json:"hostname"`
+	StartedAt         time.Time        `json:"started_at"`
+	FinishedAt        time.Time        `json:"finished_at"`
+	Details           []summaryDetail  `json:"details"`
+	Succeeded         int              `json:"succeeded"`
+	Skipped           int              `json:"skipped"`
+	Failed            int              `json:"failed"`
+	SkippedCategories []resultCategory `json:"skipped_categories,omitempty"`
+	FailedCategories  []resultCategory `json:"failed_categories,omitempty"`
+	Aborted           bool             `json:"aborted,omitempty"`
+	Unprocessed       int              `json:"unprocessed,omitempty"`
+	APIStats          apiStatsSnapshot `json:"api_stats"`
+	Results           []targetResult   `json:"results"`
+	Changes           []roleUpdate     `json:"changes,omitempty"`
+}
+
+var runsCmd = &cobra.Command{
+	Use:   "runs",
+	Short: "Review past create, edit, assign, migrate-grants, copy-permissions, and reconcile --fix runs",
+}
+
+var runsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved runs, newest first",
+	Args:  cobra.NoArgs,
+	RunE:  runRunsList,
+}
+
+var runsShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show the parameters and results of a saved run",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRunsShow,
+}
+
+func init() {
+	runsCmd.AddCommand(runsListCmd)
+	runsCmd.AddCommand(runsShowCmd)
+}
+
+func runHistoryDir() string {
+	return filepath.Join(config.StateDir(), "gh-custom-roles", "runs")
+}
+
+// newRunRecord captures a finished run. Its ID is assigned when it is saved.
+func newRunRecord(opts options, title string, details []summaryDetail, results *runResults) runRecord {
+	return runRecord{
+		Title:             title,
+		Hostname:          opts.hostname,
+		StartedAt:         results.started,
+		FinishedAt:        time.Now(),
+		Details:           details,
+		Succeeded:         results.Count(statusSucceeded),
+		Skipped:           results.Count(statusSkipped),
+		Failed:            results.Count(statusFailed),
+		SkippedCategories: results.Categories(statusSkipped),
+		FailedCategories:  results.Categories(statusFailed),
+		Aborted:           results.Aborted(),
+		Unprocessed:       results.Unprocessed(),
+		APIStats:          apiUsage.Snapshot(),
+		Results:           results.Recent(),
+		Changes:           results.Updates(),
+	}
+}
+
+// saveRunRecord stores a finished run in the history and sets its ID
+func saveRunRecord(record *runRecord) error {
+	dir := runHistoryDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	// IDs sort chronologically; a suffix separates runs in the same second
+	base := record.StartedAt.UTC().Format("20060102-150405")
+	record.ID = base
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(dir, record.ID+".json")); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		record.ID = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, record.ID+".json"), data, 0o600); err != nil {
+		return err
+	}
+
+	pruneRunHistory(dir)
+	return nil
+}
+
+// pruneRunHistory removes the oldest records beyond runHistoryLimit.
+// Failures are ignored since the records are only a convenience.
+func pruneRunHistory(dir string) {
+	ids, err := runHistoryIDs(dir)
+	if err != nil || len(ids) <= runHistoryLimit {
+		return
+	}
+	for _, id := range ids[runHistoryLimit:] {
+		_ = os.Remove(filepath.Join(dir, id+".json"))
+	}
+}
+
+// runHistoryIDs returns the saved run IDs, newest first
+func runHistoryIDs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, entry := range entries {
+		if id, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+	return ids, nil
+}
+
+func loadRunRecord(id string) (runRecord, error) {
+	var record runRecord
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return record, fmt.Errorf("invalid run ID %q", id)
+	}
+	data, err := os.ReadFile(filepath.Join(runHistoryDir(), id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return record, fmt.Errorf("no run with ID %s (list saved runs with: gh custom-roles runs list)", id)
+	}
+	if err != nil {
+		return record, err
+	}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return record, fmt.Errorf("failed to parse run %s: %w", id, err)
+	}
+	return record, nil
+}
+
+func runRunsList(_ *cobra.Command, _ []string) error {
+	ids, err := runHistoryIDs(runHistoryDir())
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		pterm.Info.Println("No runs saved yet.")
+		return nil
+	}
+
+	data := pterm.TableData{{"ID", "Started", "Host", "Run", "Succeeded", "Skipped", "Failed"}}
+	for _, id := range ids {
+		record, err := loadRunRecord(id)
+		if err != nil {
+			pterm.Warning.Printfln("Skipping run %s: %v", id, err)
+			continue
+		}
+		failed := fmt.Sprintf("%d", record.Failed)
+		if record.Aborted {
+			failed += " (aborted)"
+		}
+		data = append(data, []string{
+			record.ID,
+			record.StartedAt.Local().Format("2006-01-02 15:04"),
+			record.Hostname,
+			record.Title,
+			fmt.Sprintf("%d", record.Succeeded),
+			fmt.Sprintf("%d", record.Skipped),
+			failed,
+		})
+	}
+	return pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+}
+
+func runRunsShow(_ *cobra.Command, args []string) error {
+	record, err := loadRunRecord(args[0])
+	if err != nil {
+		return err
+	}
+
+	pterm.DefaultSection.Println(record.Title)
+	pterm.Info.Printfln("Run ID: %s", record.ID)
+	pterm.Info.Printfln("Host: %s", record.Hostname)
+	pterm.Info.Printfln("Started: %s (took %s)", record.StartedAt.Local().Format("2006-01-02 15:04:05"), record.FinishedAt.Sub(record.StartedAt).Round(time.Second))
+	for _, detail := range record.Details {
+		pterm.Info.Printfln("%s: %s", detail.Label, detail.Value)
+	}
+
+	pterm.Println()
+	pterm.DefaultSection.WithLevel(2).Println("Outcome")
+	pterm.Info.Printfln("✓ Succeeded: %d", record.Succeeded)
+	if record.Skipped > 0 {
+		pterm.Warning.Printfln("⚠ Warnings: %d", record.Skipped)
+		printCategories(record.SkippedCategories)
+	}
+	if record.Failed > 0 {
+		pterm.Error.Printfln("✗ Errors: %d", record.Failed)
+		printCategories(record.FailedCategories)
+	}
+	if record.Aborted {
+		pterm.Error.Printfln("✗ Aborted: %d targets not processed", record.Unprocessed)
+	}
+	pterm.Info.Printfln("API requests: %s", record.APIStats)
+	pterm.Info.Printfln("Retries: %d · Time rate-limited: %s", record.APIStats.Retries, record.APIStats.RateLimited())
+
+	if len(record.Results) > 0 {
+		pterm.Println()
+		pterm.DefaultSection.WithLevel(2).Println("Results")
+		if total := record.Succeeded + record.Skipped + record.Failed; len(record.Results) < total {
+			pterm.Info.Printfln("Showing the last %d of %d results", len(record.Results), total)
+		}
+		data := pterm.TableData{{"Target", "Result", "Details"}}
+		for _, result := range record.Results {
+			data = append(data, []string{result.Target, result.Status, result.Message})
+		}
+		if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+			return err
+		}
+	}
+
+	if len(record.Changes) == 0 {
+		return nil
+	}
+	pterm.Println()
+	pterm.DefaultSection.WithLevel(2).Println("Changes")
+	data := pterm.TableData{{"Target", "Role", "Role ID", "Changes"}}
+	for _, update := range record.Changes {
+		data = append(data, []string{update.Target, update.Role, fmt.Sprintf("%d", update.RoleID), update.String()})
+	}
+	return pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/spf13/cobra"
+)
+
+// Supported host types
+const (
+	hostTypeDotcom  = "GitHub.com"
+	hostTypeTenancy = "GHE.com (Enterprise Cloud with data residency)"
+	hostTypeServer  = "GitHub Enterprise Server"
+)
+
+// normalizeHostname accepts a hostname or URL (https://acme.ghe.com/,
+// api.acme.ghe.com) and returns the hostname gh uses for the instance.
+// API base paths, including api.SUBDOMAIN.ghe.com for data residency hosts,
+// are derived from this hostname by gh.
+func normalizeHostname(input string) string {
+	host := strings.TrimSpace(input)
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.IndexAny(host, "/?#"); i >= 0 {
+		host = host[:i]
+	}
+	return auth.NormalizeHostname(host)
+}
+
+// ghDefaultHost returns the host gh itself would use and where it came from:
+// GH_HOST, the only host in gh's hosts configuration, or github.com by default
+func ghDefaultHost() (string, string) {
+	return auth.DefaultHost()
+}
+
+// completeHostnames offers the hosts gh is authenticated to for --hostname
+func completeHostnames(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return auth.KnownHosts(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// hostType describes which kind of GitHub instance a hostname refers to
+func hostType(hostname string) string {
+	switch {
+	case auth.IsTenancy(hostname):
+		return hostTypeTenancy
+	case auth.IsEnterprise(hostname):
+		return hostTypeServer
+	default:
+		return hostTypeDotcom
+	}
+}
+
+// defaultEnterpriseForHost returns the enterprise slug implied by the
+// hostname. GHE.com subdomains are the enterprise slug; other hosts have no
+// implied slug.
+func defaultEnterpriseForHost(hostname string) string {
+	if !auth.IsTenancy(hostname) {
+		return ""
+	}
+	subdomain, _, _ := strings.Cut(normalizeHostname(hostname), ".")
+	return subdomain
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultRequestRate is the default --requests-per-second. It stays well
+// below GitHub's secondary rate limits even at the highest --concurrency.
+const defaultRequestRate = 10
+
+// tokenBucket allows bursts of up to capacity requests and refills at rate
+// tokens per second
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(rate float64) *tokenBucket {
+	capacity := rate
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &tokenBucket{rate: rate, capacity: capacity, tokens: capacity, last: time.Now()}
+}
+
+// Wait blocks until a token is available and takes it
+func (b *tokenBucket) Wait() {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+		time.Sleep(wait)
+	}
+}
+
+var (
+	hostLimitersMu sync.Mutex
+	hostLimiters   = map[string]*tokenBucket{}
+)
+
+// hostLimiter returns the token bucket shared by all requests to a host, or
+// nil when rate limiting is disabled
+func hostLimiter(host string) *tokenBucket {
+	if opts.requestRate <= 0 {
+		return nil
+	}
+
+	hostLimitersMu.Lock()
+	defer hostLimitersMu.Unlock()
+	limiter, ok := hostLimiters[host]
+	if !ok {
+		limiter = newTokenBucket(opts.requestRate)
+		hostLimiters[host] = limiter
+	}
+	return limiter
+}
+
+// rateLimitedTransport makes every REST and GraphQL request wait for its
+// host's token bucket, so --concurrency never turns into request bursts that
+// trip secondary rate limits on a single instance
+type rateLimitedTransport struct {
+	base http.RoundTripper
+}
+
+func (t rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if limiter := hostLimiter(req.URL.Host); limiter != nil {
+		start := time.Now()
+		limiter.Wait()
+		apiUsage.recordThrottle(time.Since(start))
+	}
+	return t.base.RoundTrip(req)
+}
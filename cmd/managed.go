@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"strings"
+)
+
+// managedMarker ends the description of roles created with --mark-managed.
+// Custom roles have no field for labels, and the description is the only
+// free-form text the API keeps, so the marker lives there.
+const managedMarker = "[managed by gh-custom-roles]"
+
+// categoryUnmanaged is the warning category for roles skipped with
+// --managed-only because they were not created by the tool
+const categoryUnmanaged = "Role not managed"
+
+// isManaged reports whether a role carries the managed marker
+func isManaged(role customRole) bool {
+	return strings.HasSuffix(strings.TrimSpace(role.Description), managedMarker)
+}
+
+// markManaged appends the managed marker to a description, once
+func markManaged(description string) string {
+	description = strings.TrimSpace(description)
+	switch {
+	case strings.HasSuffix(description, managedMarker):
+		return description
+	case description == "":
+		return managedMarker
+	default:
+		return description + " " + managedMarker
+	}
+}
+
+// excludedByManagedOnly reports whether --managed-only rules out reading or
+// changing role
+func excludedByManagedOnly(role customRole) bool {
+	return opts.managedOnly && !isManaged(role)
+}
+
+// countManaged returns how many roles carry the managed marker
+func countManaged(roles []orgRole) int {
+	count := 0
+	for _, role := range roles {
+		if isManaged(role.Role) {
+			count++
+		}
+	}
+	return count
+}
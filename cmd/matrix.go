@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// grantMatrix is every role in scope against every permission, in the order
+// the rows and columns are written
+type grantMatrix struct {
+	Roles       []orgRole
+	Permissions []string
+	// Unavailable are granted permissions missing from the host's catalog
+	Unavailable map[string]bool
+}
+
+var grantMatrixCmd = &cobra.Command{
+	Use:   "grant-matrix",
+	Short: "Write a matrix of custom roles against fine-grained permissions for access certification",
+	Args:  cobra.NoArgs,
+	RunE:  runGrantMatrix,
+}
+
+func init() {
+	// Grant matrix command flags
+	grantMatrixCmd.Flags().StringVar(&opts.grantMatrixFormat, "format", formatCSV, "Output format: csv or markdown")
+	grantMatrixCmd.Flags().StringVar(&opts.outputPath, "output", "", "Write the matrix to this file instead of stdout")
+}
+
+func runGrantMatrix(_ *cobra.Command, _ []string) error {
+	switch opts.outputFormat {
+	case formatCSV, formatMarkdown:
+		if opts.outputPath == "" {
+			pterm.SetDefaultOutput(os.Stderr)
+		}
+	default:
+		return fmt.Errorf("invalid format %q: expected %s or %s", opts.outputFormat, formatCSV, formatMarkdown)
+	}
+
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	if err := selectTargets(); err != nil {
+		return err
+	}
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, opts.allOrgs); err != nil {
+		return err
+	}
+
+	if err := resolveEnterprise(); err != nil {
+		return err
+	}
+
+	orgs, err := resolveOrganizations(opts)
+	if err != nil {
+		return err
+	}
+	if len(orgs) == 0 {
+		return errors.New("no organizations provided")
+	}
+
+	// Validate concurrency and delay bounds
+	if err := validatePacing(opts); err != nil {
+		return err
+	}
+
+	catalog, err := listFineGrainedPermissions(opts.hostname, orgs[0])
+	if err != nil {
+		return err
+	}
+	inventory, err := collectCustomRoles(opts, orgs)
+	if err != nil {
+		return err
+	}
+	matrix := buildGrantMatrix(inventory.Roles, catalog)
+
+	if err := exportGrantMatrix(opts, matrix); err != nil {
+		return fmt.Errorf("failed to write grant matrix: %w", err)
+	}
+
+	// Display summary
+	pterm.Println()
+	pterm.DefaultSection.Println("Summary")
+	if opts.outputPath != "" {
+		pterm.Success.Printfln("Wrote the grant matrix to %s", opts.outputPath)
+	}
+	pterm.Info.Printfln("✓ Roles: %d in %d organizations", len(matrix.Roles), len(inventory.Orgs))
+	pterm.Info.Printfln("Permissions: %d", len(matrix.Permissions))
+	if len(matrix.Unavailable) > 0 {
+		pterm.Warning.Printfln("⚠ Granted permissions missing from the host's catalog: %d (marked in the header)", len(matrix.Unavailable))
+	}
+	if inventory.ErrorCount > 0 {
+		pterm.Error.Printfln("✗ Errors: %d", inventory.ErrorCount)
+	}
+
+	// Display command for replication
+	printReplicationTip("this matrix", buildGrantMatrixReplicationCommand(opts))
+
+	if inventory.ErrorCount > 0 {
+		return fmt.Errorf("completed with %d errors", inventory.ErrorCount)
+	}
+	return nil
+}
+
+// buildGrantMatrix orders roles by organization and name, and permissions
+// by name. Permissions a role grants that are not in the catalog get a
+// column of their own so no grant is left out.
+func buildGrantMatrix(roles []orgRole, catalog []fineGrainedPermission) grantMatrix {
+	matrix := grantMatrix{Roles: append([]orgRole(nil), roles...), Unavailable: map[string]bool{}}
+	sort.SliceStable(matrix.Roles, func(i, j int) bool {
+		a, b := matrix.Roles[i], matrix.Roles[j]
+		if a.Org != b.Org {
+			return a.Org < b.Org
+		}
+		return a.Role.Name < b.Role.Name
+	})
+
+	available := map[string]bool{}
+	for _, permission := range catalog {
+		available[permission.Name] = true
+		matrix.Permissions = append(matrix.Permissions, permission.Name)
+	}
+	for _, role := range roles {
+		for _, permission := range role.Role.Permissions {
+			if !available[permission] && !matrix.Unavailable[permission] {
+				matrix.Unavailable[permission] = true
+				matrix.Permissions = append(matrix.Permissions, permission)
+			}
+		}
+	}
+	sort.Strings(matrix.Permissions)
+	return matrix
+}
+
+// header returns the column name of a permission, flagging permissions that
+// are missing from the catalog
+func (m grantMatrix) header(permission string) string {
+	if m.Unavailable[permission] {
+		return permission + " (unavailable)"
+	}
+	return permission
+}
+
+// exportGrantMatrix writes the matrix in opts.outputFormat to opts.outputPath,
+// or to stdout when no path is given
+func exportGrantMatrix(opts options, matrix grantMatrix) error {
+	var out io.Writer = os.Stdout
+	if opts.outputPath != "" {
+		file, err := os.Create(filepath.Clean(opts.outputPath))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	var err error
+	if opts.outputFormat == formatMarkdown {
+		err = writeGrantMatrixMarkdown(out, matrix)
+	} else {
+		err = writeGrantMatrixCSV(out, matrix)
+	}
+	if err != nil {
+		return err
+	}
+	if file, ok := out.(*os.File); ok && file != os.Stdout {
+		return file.Close()
+	}
+	return nil
+}
+
+// writeGrantMatrixCSV writes one row per role with an "x" in every granted
+// permission column
+func writeGrantMatrixCSV(out io.Writer, matrix grantMatrix) error {
+	writer := csv.NewWriter(out)
+	header := withHostColumn("host", []string{"org", "role", "base_role"})
+	for _, permission := range matrix.Permissions {
+		header = append(header, matrix.header(permission))
+	}
+	_ = writer.Write(header)
+	for _, role := range matrix.Roles {
+		row := withHostColumn(opts.hostname, []string{role.Org, role.Role.Name, role.Role.BaseRole})
+		_ = writer.Write(append(row, grantCells(role.Role, matrix.Permissions, "x")...))
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeGrantMatrixMarkdown writes the matrix as a GitHub-flavored markdown
+// table with a check mark in every granted permission column
+func writeGrantMatrixMarkdown(out io.Writer, matrix grantMatrix) error {
+	var b strings.Builder
+	header := withHostColumn("Host", []string{"Organization", "Role", "Base Role"})
+	for _, permission := range matrix.Permissions {
+		header = append(header, escapeMarkdown(matrix.header(permission)))
+	}
+	b.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	b.WriteString(strings.Repeat("|---", len(header)) + "|\n")
+	for _, role := range matrix.Roles {
+		cells := withHostColumn(escapeMarkdown(opts.hostname), []string{escapeMarkdown(role.Org), escapeMarkdown(role.Role.Name), role.Role.BaseRole})
+		cells = append(cells, grantCells(role.Role, matrix.Permissions, "✓")...)
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// grantCells returns mark for each permission the role grants and an empty
+// cell for the rest
+func grantCells(role customRole, permissions []string, mark string) []string {
+	granted := map[string]bool{}
+	for _, permission := range role.Permissions {
+		granted[permission] = true
+	}
+	cells := make([]string, len(permissions))
+	for i, permission := range permissions {
+		if granted[permission] {
+			cells[i] = mark
+		}
+	}
+	return cells
+}
+
+func buildGrantMatrixReplicationCommand(opts options) string {
+	cmd := "gh custom-roles grant-matrix"
+
+	if opts.hostname != "" {
+		cmd += " --hostname " + shellQuote(opts.hostname)
+	}
+	cmd += targetFlags(opts)
+	if opts.outputFormat != formatCSV {
+		cmd += " --format " + opts.outputFormat
+	}
+	if opts.outputPath != "" {
+		cmd += " --output " + shellQuote(opts.outputPath)
+	}
+	cmd += pacingFlags(opts)
+
+	return cmd
+}
@@ -0,0 +1,409 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+type grantChange struct {
+	Org         string
+	Repo        string
+	GranteeType string
+	Grantee     string
+	FromRole    string
+	ToRole      string
+}
+
+type repoTeam struct {
+	Slug       string `json:"slug"`
+	Permission string `json:"permission"`
+	RoleName   string `json:"role_name"`
+}
+
+type repoCollaborator struct {
+	Login    string `json:"login"`
+	RoleName string `json:"role_name"`
+}
+
+// repoGrant is the role a team or direct collaborator holds on a repository
+type repoGrant struct {
+	Org         string
+	Repo        string
+	GranteeType string
+	Grantee     string
+	Role        string
+}
+
+var migrateGrantsCmd = &cobra.Command{
+	Use:         "migrate-grants",
+	Short:       "Convert existing base role grants on repositories to a custom role",
+	RunE:        runMigrateGrants,
+	Annotations: mutatingCommand,
+}
+
+func init() {
+	// Migrate grants command flags
+	migrateGrantsCmd.Flags().StringVar(&opts.fromRole, "from-role", "", "Base role to migrate away from (read, triage, write, maintain, admin)")
+	migrateGrantsCmd.Flags().StringVar(&opts.toRole, "to-role", "", "Custom role name to grant instead")
+	migrateGrantsCmd.Flags().StringVar(&opts.repoPattern, "repos", "", "Only migrate repositories matching this glob pattern (default all)")
+	migrateGrantsCmd.Flags().StringVar(&opts.confirmEnterprise, "confirm-enterprise", "", "Enterprise slug, retyped to approve migrating grants with --all-orgs")
+}
+
+func runMigrateGrants(_ *cobra.Command, _ []string) error {
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	if err := selectTargets(); err != nil {
+		return err
+	}
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, opts.allOrgs); err != nil {
+		return err
+	}
+
+	if err := resolveEnterprise(); err != nil {
+		return err
+	}
+
+	orgs, err := resolveOrganizations(opts)
+	if err != nil {
+		return err
+	}
+	if len(orgs) == 0 {
+		return errors.New("no organizations provided")
+	}
+
+	fromRole, err := resolveFromRole(opts.fromRole)
+	if err != nil {
+		return err
+	}
+	opts.fromRole = fromRole
+
+	if opts.toRole == "" {
+		opts.toRole, err = promptText("Custom role name to migrate to")
+		if err != nil {
+			return err
+		}
+	}
+	opts.toRole = strings.TrimSpace(opts.toRole)
+	if opts.toRole == "" {
+		return errors.New("custom role name is required")
+	}
+
+	// Validate concurrency and delay bounds
+	if err := validatePacing(opts); err != nil {
+		return err
+	}
+
+	changes, err := findGrantChanges(opts, orgs)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		pterm.Info.Printfln("No teams or users hold %s on the selected repositories. Nothing to migrate.", opts.fromRole)
+		return nil
+	}
+
+	// Display confirmation before migrating grants
+	pterm.Println()
+	pterm.DefaultSection.Println("Confirmation")
+	printAccount(opts.hostname)
+	pterm.Info.Printfln("From Role: %s", opts.fromRole)
+	pterm.Info.Printfln("To Role: %s", opts.toRole)
+	pterm.Info.Printfln("Grants to migrate: %d", len(changes))
+	checkRateLimitBudget(opts.hostname, len(changes))
+	pterm.Println()
+	if err := printGrantChanges(changes); err != nil {
+		return err
+	}
+	pterm.Println()
+
+	confirm, err := confirmRun("Begin grant migration?")
+	if err != nil {
+		return err
+	}
+	if !confirm {
+		pterm.Info.Println("Grant migration cancelled.")
+		return nil
+	}
+	if err := confirmEnterprise(opts, "migrate grants"); err != nil {
+		return err
+	}
+	pterm.Println()
+
+	savePromptDefaults(opts, "")
+
+	results := newRunResults(len(changes))
+
+	progressBar, err := startProgressbar(len(changes), "Migrating grants")
+	if err != nil {
+		return err
+	}
+	defer progressBar.Stop()
+
+	var applied []grantChange
+	var appliedMu sync.Mutex
+	results.Track(progressBar)
+
+	processQueue(opts, untilStopped(queueTargets(changes), results.Stopped()), func(change grantChange) {
+		applyErr := applyGrantChange(opts.hostname, change)
+		target := change.Org + "/" + change.Repo + " (" + change.GranteeType + " " + change.Grantee + ")"
+		switch {
+		case applyErr != nil && isNotFoundError(applyErr):
+			results.Skipped(target, "Grantee or repository not found", "%s %s on %s/%s no longer exists. Skipping.", change.GranteeType, change.Grantee, change.Org, change.Repo)
+		case applyErr != nil:
+			results.Failed(target, applyErr.Error(), "Failed to migrate %s %s on %s/%s: %v", change.GranteeType, change.Grantee, change.Org, change.Repo, applyErr)
+		default:
+			appliedMu.Lock()
+			applied = append(applied, change)
+			appliedMu.Unlock()
+			results.Succeeded(target, change.FromRole+" → "+change.ToRole, "Migrated %s %s on %s/%s from %s to %s", change.GranteeType, change.Grantee, change.Org, change.Repo, change.FromRole, change.ToRole)
+		}
+	})
+
+	progressBar.Stop()
+
+	// Display summary
+	pterm.Println()
+	pterm.DefaultSection.Println("Summary")
+	results.printSummaryCounts("migrated")
+	if len(applied) > 0 {
+		pterm.Println()
+		pterm.DefaultSection.WithLevel(2).Println("Changed grants")
+		if err := printGrantChanges(applied); err != nil {
+			return err
+		}
+	}
+
+	// Display command for replication
+	cmd := buildMigrateGrantsReplicationCommand(opts)
+	printReplicationTip("these changes", cmd)
+
+	// Publish the run summary (GitHub Actions step summary, tracking issue)
+	details := []summaryDetail{
+		{Label: "From Role", Value: opts.fromRole},
+		{Label: "To Role", Value: opts.toRole},
+		{Label: "Grants to migrate", Value: fmt.Sprintf("%d", len(changes))},
+		{Label: "Replication Command", Value: "`" + cmd + "`"},
+	}
+	publishRunSummary(opts, "Grant migration: "+opts.fromRole+" → "+opts.toRole, details, results)
+
+	return results.Err()
+}
+
+func resolveFromRole(role string) (string, error) {
+	options := []string{"read", "triage", "write", "maintain", "admin"}
+	if role != "" {
+		role = strings.ToLower(strings.TrimSpace(role))
+		for _, option := range options {
+			if role == option {
+				return role, nil
+			}
+		}
+		return "", fmt.Errorf("invalid base role: %s", role)
+	}
+
+	choice, err := promptSelect("Select base role to migrate from", options, "")
+	if err != nil {
+		return "", err
+	}
+	return choice, nil
+}
+
+// findGrantChanges scans the selected repositories in each organization for
+// teams and direct collaborators holding opts.fromRole
+func findGrantChanges(opts options, orgs []string) ([]grantChange, error) {
+	grants, err := scanRepoGrants(opts, orgs, func(grant repoGrant) bool {
+		return grant.Role == opts.fromRole
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]grantChange, 0, len(grants))
+	for _, grant := range grants {
+		changes = append(changes, grantChange{Org: grant.Org, Repo: grant.Repo, GranteeType: grant.GranteeType, Grantee: grant.Grantee, FromRole: opts.fromRole, ToRole: opts.toRole})
+	}
+	return changes, nil
+}
+
+// scanRepoGrants lists the team and direct collaborator grants on the
+// repositories of each organization (limited by opts.repoPattern) and returns
+// those accepted by match, sorted by organization, repository and grantee
+func scanRepoGrants(opts options, orgs []string, match func(repoGrant) bool) ([]repoGrant, error) {
+	type orgRepo struct {
+		Org  string
+		Repo string
+	}
+
+	var targets []orgRepo
+	for _, org := range orgs {
+		repos, err := listOrgRepositories(opts.hostname, org)
+		if err != nil {
+			if isNotFoundError(err) {
+				pterm.Warning.Printfln("Organization %s not found. Skipping.", org)
+				continue
+			}
+			return nil, err
+		}
+		for _, repo := range repos {
+			if opts.repoPattern != "" {
+				ok, err := path.Match(strings.ToLower(opts.repoPattern), strings.ToLower(repo))
+				if err != nil {
+					return nil, fmt.Errorf("invalid repository pattern %q: %w", opts.repoPattern, err)
+				}
+				if !ok {
+					continue
+				}
+			}
+			targets = append(targets, orgRepo{Org: org, Repo: repo})
+		}
+	}
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	progressBar, err := startProgressbar(len(targets), "Scanning repository grants")
+	if err != nil {
+		return nil, err
+	}
+	defer progressBar.Stop()
+
+	var grants []repoGrant
+	var scanErr error
+	var mu sync.Mutex
+
+	processTargets(opts, targets, func(target orgRepo) {
+		found, err := listRepoGrants(opts.hostname, target.Org, target.Repo)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil && scanErr == nil {
+			scanErr = fmt.Errorf("failed to scan %s/%s: %w", target.Org, target.Repo, err)
+		}
+		for _, grant := range found {
+			if match(grant) {
+				grants = append(grants, grant)
+			}
+		}
+		progressBar.Increment()
+	})
+
+	if scanErr != nil {
+		return nil, scanErr
+	}
+
+	sort.Slice(grants, func(i, j int) bool {
+		a, b := grants[i], grants[j]
+		if a.Org != b.Org {
+			return a.Org < b.Org
+		}
+		if a.Repo != b.Repo {
+			return a.Repo < b.Repo
+		}
+		if a.GranteeType != b.GranteeType {
+			return a.GranteeType < b.GranteeType
+		}
+		return a.Grantee < b.Grantee
+	})
+	return grants, nil
+}
+
+// listRepoGrants returns the roles held by teams and direct collaborators on
+// a repository
+func listRepoGrants(hostname, org, repo string) ([]repoGrant, error) {
+	var grants []repoGrant
+
+	response, stderr, err := ghAPI(hostname, "--paginate", "repos/"+org+"/"+repo+"/teams?per_page=100")
+	if err != nil {
+		return nil, fmt.Errorf("team lookup failed: %w (%s)", err, stderr.String())
+	}
+	teams, err := decodeArrayPages[repoTeam](response.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	for _, team := range teams {
+		grants = append(grants, repoGrant{Org: org, Repo: repo, GranteeType: "team", Grantee: team.Slug, Role: teamRoleName(team)})
+	}
+
+	response, stderr, err = ghAPI(hostname, "--paginate", "repos/"+org+"/"+repo+"/collaborators?affiliation=direct&per_page=100")
+	if err != nil {
+		return nil, fmt.Errorf("collaborator lookup failed: %w (%s)", err, stderr.String())
+	}
+	collaborators, err := decodeArrayPages[repoCollaborator](response.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	for _, collaborator := range collaborators {
+		grants = append(grants, repoGrant{Org: org, Repo: repo, GranteeType: "user", Grantee: collaborator.Login, Role: collaborator.RoleName})
+	}
+
+	return grants, nil
+}
+
+// teamRoleName returns the role a team holds on a repository, translating the
+// legacy permission names returned by the teams endpoint into role names
+func teamRoleName(team repoTeam) string {
+	if team.RoleName != "" {
+		return team.RoleName
+	}
+	switch team.Permission {
+	case "pull":
+		return "read"
+	case "push":
+		return "write"
+	default:
+		return team.Permission
+	}
+}
+
+func applyGrantChange(hostname string, change grantChange) error {
+	if change.GranteeType == "team" {
+		return assignTeamRepoRole(hostname, teamRepoAssignment{Org: change.Org, Team: change.Grantee, Repo: change.Repo, Role: change.ToRole})
+	}
+
+	_, stderr, err := ghAPI(hostname,
+		"-X", "PUT",
+		"repos/"+change.Org+"/"+change.Repo+"/collaborators/"+change.Grantee,
+		"-f", "permission="+change.ToRole,
+	)
+	if err != nil {
+		return fmt.Errorf("update collaborator failed: %w (%s)", err, stderr.String())
+	}
+	return nil
+}
+
+func printGrantChanges(changes []grantChange) error {
+	data := pterm.TableData{{"Organization", "Repository", "Type", "Grantee", "From", "To"}}
+	for _, change := range changes {
+		data = append(data, []string{change.Org, change.Repo, change.GranteeType, change.Grantee, change.FromRole, change.ToRole})
+	}
+	return pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+}
+
+func buildMigrateGrantsReplicationCommand(opts options) string {
+	cmd := "gh custom-roles migrate-grants"
+
+	if opts.hostname != "" {
+		cmd += " --hostname " + shellQuote(opts.hostname)
+	}
+	cmd += targetFlags(opts)
+	cmd += " --from-role " + shellQuote(opts.fromRole)
+	cmd += " --to-role " + shellQuote(opts.toRole)
+	if opts.repoPattern != "" {
+		cmd += " --repos " + shellQuote(opts.repoPattern)
+	}
+	cmd += pacingFlags(opts)
+
+	return cmd
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// Environment variables that hand one host of a multi-host run to a child
+// process, tell it where to leave its error for the hosts table, and tell it
+// the run was already confirmed
+const (
+	hostRunEnv       = "GH_CUSTOM_ROLES_HOST_RUN"
+	hostResultEnv    = "GH_CUSTOM_ROLES_HOST_RESULT"
+	hostConfirmedEnv = "GH_CUSTOM_ROLES_HOST_CONFIRMED"
+)
+
+// runHost is the host this process was started for by a multi-host run, or
+// empty when it runs on its own
+var runHost string
+
+// runConfirmed reports whether the multi-host run that started this process
+// was confirmed once for all hosts, so confirmRun does not ask again
+var runConfirmed bool
+
+// hostRun is the outcome of a command on one of several --hostname hosts
+type hostRun struct {
+	Hostname string
+	Err      error
+}
+
+// setHostnames reads the repeated --hostname flag. A single host is used as
+// is; several hosts are run at the same time by forEachHost. A process
+// started for one host of a multi-host run takes its host from the
+// environment, writes its own --output file, and never prompts.
+func setHostnames() {
+	if runHost = os.Getenv(hostRunEnv); runHost != "" {
+		opts.hostnames = []string{runHost}
+		opts.hostname = runHost
+		if opts.outputPath != "" {
+			opts.outputPath = hostOutputPath(opts.outputPath, runHost)
+		}
+		runConfirmed = os.Getenv(hostConfirmedEnv) != ""
+		noPrompts = true
+		return
+	}
+	opts.hostnames = uniqueStrings(opts.hostnames)
+	if len(opts.hostnames) == 1 {
+		opts.hostname = opts.hostnames[0]
+	}
+}
+
+// multiHost reports whether the command runs against several hosts
+func multiHost() bool {
+	return len(opts.hostnames) > 1
+}
+
+// hostColumns reports whether results and exported rows name their host,
+// which they do in every part of a multi-host run
+func hostColumns() bool {
+	return runHost != "" || multiHost()
+}
+
+// forEachHost wraps the RunE of every subcommand so that repeated --hostname
+// flags run the command on every host at the same time, for example GitHub
+// Enterprise Server and GitHub Enterprise Cloud during a migration. Each host
+// runs in its own process started with the same arguments, so it gets its own
+// account, client, scope validation, pacing, and run summary. The hosts cannot
+// prompt, so commands that change roles or grants are confirmed once here,
+// unless --yes was passed.
+func forEachHost(command *cobra.Command) {
+	for _, sub := range command.Commands() {
+		forEachHost(sub)
+	}
+	run := command.RunE
+	if run == nil {
+		return
+	}
+	command.RunE = func(cmd *cobra.Command, args []string) error {
+		if !multiHost() {
+			return run(cmd, args)
+		}
+		if opts.outputPath == "" && opts.outputFormat != "" && opts.outputFormat != formatTable {
+			return fmt.Errorf("--format %s with several --hostname hosts needs --output, which writes one file per host", opts.outputFormat)
+		}
+		executable, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to start the host runs: %w", err)
+		}
+
+		hostnames := make([]string, len(opts.hostnames))
+		for i, hostname := range opts.hostnames {
+			hostnames[i] = normalizeHostname(hostname)
+		}
+		pterm.Info.Printfln("Running on %s at the same time: %s", pluralize(len(hostnames), "host", "hosts"), strings.Join(hostnames, ", "))
+
+		confirmed := false
+		if cmd.Annotations["mutating"] == "true" && !opts.assumeYes {
+			message := fmt.Sprintf("Run %s on %s? The hosts start without asking again", cmd.Name(), pluralize(len(hostnames), "host", "hosts"))
+			if err := checkPrompt(message); err != nil {
+				if cmd.Flags().Lookup("yes") == nil {
+					return fmt.Errorf("%s on several hosts must be confirmed before the hosts start, and this run cannot prompt; run it interactively or on one host at a time", cmd.Name())
+				}
+				return fmt.Errorf("%s on several hosts must be confirmed before the hosts start, and this run cannot prompt; pass --yes", cmd.Name())
+			}
+			confirm, err := promptConfirm(message)
+			if err != nil {
+				return err
+			}
+			if !confirm {
+				pterm.Info.Println("Run cancelled.")
+				return nil
+			}
+			confirmed = true
+		}
+
+		// Each host's output is held back until it finishes, then printed
+		// as one block so hosts do not interleave
+		runs := make([]hostRun, len(hostnames))
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		for i, hostname := range hostnames {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, output := runOnHost(executable, hostname, confirmed)
+				runs[i] = result
+
+				mu.Lock()
+				defer mu.Unlock()
+				pterm.Println()
+				pterm.DefaultHeader.Println(hostname)
+				pterm.Print(string(output))
+			}()
+		}
+		wg.Wait()
+		return printHostRuns(runs)
+	}
+}
+
+// runOnHost runs this command again in a child process for hostname and
+// returns its outcome and combined output. Bare --stream events go straight
+// to stdout, since every event is a single line naming its host.
+func runOnHost(executable, hostname string, confirmed bool) (hostRun, []byte) {
+	result := hostRun{Hostname: hostname}
+	resultFile, err := os.CreateTemp("", "gh-custom-roles-host-*")
+	if err != nil {
+		result.Err = fmt.Errorf("failed to start: %w", err)
+		return result, nil
+	}
+	_ = resultFile.Close()
+	defer os.Remove(resultFile.Name())
+
+	var output bytes.Buffer
+	child := exec.Command(executable, os.Args[1:]...)
+	child.Env = append(os.Environ(), hostRunEnv+"="+hostname, hostResultEnv+"="+resultFile.Name())
+	if confirmed {
+		child.Env = append(child.Env, hostConfirmedEnv+"=1")
+	}
+	child.Stdout, child.Stderr = &output, &output
+	if opts.stream == "-" {
+		child.Stdout = os.Stdout
+	}
+	err = child.Run()
+	if err == nil {
+		return result, output.Bytes()
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		result.Err = fmt.Errorf("failed to start: %w", err)
+		return result, output.Bytes()
+	}
+	message, _ := os.ReadFile(resultFile.Name())
+	result.Err = errors.New(strings.TrimSpace(string(message)))
+	if len(bytes.TrimSpace(message)) == 0 {
+		result.Err = exitErr
+	}
+	if code := exitErr.ExitCode(); code > 1 {
+		result.Err = &exitCodeError{code: code, err: result.Err}
+	}
+	return result, output.Bytes()
+}
+
+// reportHostResult leaves err for the process that started this one for a
+// host of a multi-host run
+func reportHostResult(err error) {
+	if path := os.Getenv(hostResultEnv); path != "" {
+		_ = os.WriteFile(filepath.Clean(path), []byte(redactSecrets(err.Error())), 0o600)
+	}
+}
+
+// hostOutputPath inserts hostname before the extension of path, so every
+// host writes its own file: roles.csv becomes roles.github.com.csv
+func hostOutputPath(path, hostname string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + hostname + ext
+}
+
+// printHostRuns shows the result of every host and returns the error the
+// process should exit with. Plain errors take precedence over exit code
+// errors, such as drift found by reconcile, and lower exit codes over higher.
+func printHostRuns(runs []hostRun) error {
+	pterm.Println()
+	pterm.DefaultSection.Println("Hosts")
+
+	data := pterm.TableData{{"Host", "Result"}}
+	var failed []string
+	var exitErr *exitCodeError
+	plainError := false
+	for _, run := range runs {
+		result := "✓ completed"
+		if run.Err != nil {
+			result = "✗ " + redactSecrets(run.Err.Error())
+			failed = append(failed, run.Hostname)
+			var codeErr *exitCodeError
+			switch {
+			case !errors.As(run.Err, &codeErr):
+				plainError = true
+			case exitErr == nil || codeErr.code < exitErr.code:
+				exitErr = codeErr
+			}
+		}
+		data = append(data, []string{run.Hostname, result})
+	}
+	if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+		return err
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+	err := fmt.Errorf("failed on %s: %s", pluralize(len(failed), "host", "hosts"), strings.Join(failed, ", "))
+	if !plainError && exitErr != nil {
+		return &exitCodeError{code: exitErr.code, err: err}
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+func TestMultiHostMutatingRunNeedsConfirmation(t *testing.T) {
+	pterm.SetDefaultOutput(io.Discard)
+	saved := opts
+	t.Cleanup(func() {
+		pterm.SetDefaultOutput(os.Stdout)
+		opts = saved
+		noPrompts = false
+	})
+	opts.hostnames = []string{"github.example.com", "github.com"}
+	noPrompts = true
+
+	started := false
+	command := &cobra.Command{
+		Use:         "delete",
+		Annotations: mutatingCommand,
+		RunE: func(*cobra.Command, []string) error {
+			started = true
+			return nil
+		},
+	}
+	command.Flags().Bool("yes", false, "")
+	forEachHost(command)
+
+	err := command.RunE(command, nil)
+	if err == nil || !strings.Contains(err.Error(), "pass --yes") {
+		t.Fatalf("RunE() error = %v, want an error asking for --yes", err)
+	}
+	if started {
+		t.Error("the command ran without confirmation")
+	}
+}
+
+func TestConfirmRunSkipsConfirmedHosts(t *testing.T) {
+	noPrompts = true
+	runConfirmed = true
+	t.Cleanup(func() {
+		noPrompts = false
+		runConfirmed = false
+	})
+
+	confirm, err := confirmRun("Begin role assignment?")
+	if err != nil || !confirm {
+		t.Errorf("confirmRun() = %v, %v; want true for a confirmed multi-host run", confirm, err)
+	}
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// roleNameKey returns the form used to compare role names. GitHub treats
+// role names case-insensitively, so names are NFC-normalized and Unicode
+// case-folded: "Straße" matches "STRASSE" and a precomposed "é" matches "e"
+// followed by a combining accent, which strings.ToLower does not handle.
+// Folding is language-neutral: the Turkish dotted İ and dotless ı stay
+// distinct from i, and full-width letters from their ASCII forms.
+func roleNameKey(name string) string {
+	return cases.Fold().String(norm.NFC.String(strings.TrimSpace(name)))
+}
+
+// sameRoleName reports whether two role names refer to the same role
+func sameRoleName(a, b string) bool {
+	return roleNameKey(a) == roleNameKey(b)
+}
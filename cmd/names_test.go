@@ -0,0 +1,33 @@
+package cmd
+
+import "testing"
+
+func TestRoleNameKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{name: "ASCII case", a: "Developer", b: "DEVELOPER", same: true},
+		{name: "surrounding spaces", a: "  Developer ", b: "developer", same: true},
+		{name: "sharp s and SS", a: "Straße", b: "STRASSE", same: true},
+		{name: "sharp s and ss", a: "straße", b: "strasse", same: true},
+		{name: "capital sharp s", a: "STRA\u1e9eE", b: "strasse", same: true},
+		{name: "Turkish dotted capital I", a: "\u0130nceleme", b: "i\u0307nceleme", same: true},
+		{name: "Turkish dotted capital I and plain i", a: "\u0130nceleme", b: "inceleme", same: false},
+		{name: "Turkish dotless i and plain I", a: "\u0131nceleme", b: "Inceleme", same: false},
+		{name: "Turkish dotless i and itself uppercased", a: "\u0131nceleme", b: "\u0131NCELEME", same: true},
+		{name: "NFC and NFD", a: "R\u00e9viseur", b: "Re\u0301viseur", same: true},
+		{name: "NFD uppercase and NFC", a: "RE\u0301VISEUR", b: "r\u00e9viseur", same: true},
+		{name: "accent and no accent", a: "R\u00e9viseur", b: "Reviseur", same: false},
+		{name: "full-width case", a: "Ｄｅｖｅｌｏｐｅｒ", b: "ＤＥＶＥＬＯＰＥＲ", same: true},
+		{name: "full-width and ASCII", a: "Ｄｅｖｅｌｏｐｅｒ", b: "Developer", same: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameRoleName(tt.a, tt.b); got != tt.same {
+				t.Errorf("sameRoleName(%q, %q) = %v, want %v (keys %q and %q)", tt.a, tt.b, got, tt.same, roleNameKey(tt.a), roleNameKey(tt.b))
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// Role name casing conventions accepted by --name-case
+const (
+	nameCaseKeep   = "keep"
+	nameCaseCommon = "common"
+	nameCaseTitle  = "title"
+	nameCaseLower  = "lower"
+	nameCaseUpper  = "upper"
+)
+
+var nameCases = []string{nameCaseKeep, nameCaseCommon, nameCaseTitle, nameCaseLower, nameCaseUpper}
+
+// roleNormalization is the normalized form of one role and the changes
+// needed to reach it
+type roleNormalization struct {
+	Org         string
+	Role        customRole
+	Permissions []string
+	Changes     []roleChange
+}
+
+var normalizeCmd = &cobra.Command{
+	Use:         "normalize",
+	Short:       "Rewrite existing custom roles to a normalized form across organizations",
+	RunE:        runNormalize,
+	Annotations: mutatingCommand,
+}
+
+func init() {
+	// Normalize command flags
+	normalizeCmd.Flags().StringVarP(&opts.roleName, "role-name", "n", "", "Only normalize the role with this name (default all custom roles)")
+	normalizeCmd.Flags().StringVar(&opts.nameCase, "name-case", nameCaseKeep, "Role name casing: keep, common (the most used spelling across organizations), title, lower, or upper")
+	normalizeCmd.Flags().BoolVarP(&opts.assumeYes, "yes", "y", false, "Skip the confirmation prompt")
+	normalizeCmd.Flags().StringVar(&opts.confirmEnterprise, "confirm-enterprise", "", "Enterprise slug, retyped to approve normalizing roles with --all-orgs")
+	_ = normalizeCmd.RegisterFlagCompletionFunc("name-case", cobra.FixedCompletions(nameCases, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func runNormalize(_ *cobra.Command, _ []string) error {
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	opts.nameCase = strings.ToLower(strings.TrimSpace(opts.nameCase))
+	if !slices.Contains(nameCases, opts.nameCase) {
+		return fmt.Errorf("invalid --name-case %q: expected one of %s", opts.nameCase, strings.Join(nameCases, ", "))
+	}
+
+	if err := selectTargets(); err != nil {
+		return err
+	}
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, opts.allOrgs); err != nil {
+		return err
+	}
+
+	if err := resolveEnterprise(); err != nil {
+		return err
+	}
+
+	orgs, err := resolveOrganizations(opts)
+	if err != nil {
+		return err
+	}
+	if len(orgs) == 0 {
+		return errors.New("no organizations provided")
+	}
+
+	// Validate concurrency and delay bounds
+	if err := validatePacing(opts); err != nil {
+		return err
+	}
+
+	inventory, err := collectCustomRoles(opts, orgs)
+	if err != nil {
+		return err
+	}
+	plan := planNormalization(inventory.Roles, strings.TrimSpace(opts.roleName), opts.nameCase)
+
+	if len(plan) == 0 {
+		pterm.Println()
+		pterm.Info.Println("Every role is already in normalized form.")
+		if inventory.ErrorCount > 0 {
+			return fmt.Errorf("completed with %d errors", inventory.ErrorCount)
+		}
+		return nil
+	}
+
+	// Show the changes for every organization before applying any of them
+	byOrg := map[string][]roleNormalization{}
+	var planOrgs []string
+	for _, normalization := range plan {
+		if _, ok := byOrg[normalization.Org]; !ok {
+			planOrgs = append(planOrgs, normalization.Org)
+		}
+		byOrg[normalization.Org] = append(byOrg[normalization.Org], normalization)
+	}
+	for _, org := range planOrgs {
+		pterm.Println()
+		pterm.DefaultSection.WithLevel(2).Println(org)
+		data := pterm.TableData{{"Role", "Field", "Current", "New"}}
+		for _, normalization := range byOrg[org] {
+			for _, change := range normalization.Changes {
+				data = append(data, []string{normalization.Role.Name, change.Field, change.Current, change.New})
+			}
+		}
+		if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+			return err
+		}
+	}
+
+	// Display confirmation before normalizing roles
+	pterm.Println()
+	pterm.DefaultSection.Println("Confirmation")
+	printAccount(opts.hostname)
+	pterm.Info.Printfln("Name Casing: %s", opts.nameCase)
+	pterm.Info.Printfln("Roles to normalize: %d in %d organizations", len(plan), len(planOrgs))
+
+	// Each role needs one update request
+	checkRateLimitBudget(opts.hostname, len(plan))
+	pterm.Println()
+
+	confirm := opts.assumeYes
+	if !confirm {
+		confirm, err = confirmRun("Apply these changes?")
+		if err != nil {
+			return err
+		}
+	}
+	if !confirm {
+		pterm.Info.Println("Role normalization cancelled.")
+		return nil
+	}
+	if err := confirmEnterprise(opts, "normalize custom roles"); err != nil {
+		return err
+	}
+	pterm.Println()
+
+	results := newRunResults(len(planOrgs))
+
+	progressBar, err := startProgressbar(len(planOrgs), "Normalizing custom roles")
+	if err != nil {
+		return err
+	}
+	defer progressBar.Stop()
+
+	results.Track(progressBar)
+
+	processQueue(opts, untilStopped(queueTargets(planOrgs), results.Stopped()), func(org string) {
+		var failures []string
+		var validationCategory string
+		for _, normalization := range byOrg[org] {
+			updateErr := updateCustomRole(opts.hostname, org, normalization.Role.ID, normalization.Changes, normalization.Permissions)
+			if updateErr == nil {
+				results.RecordUpdate(org, normalization.Role, normalization.Changes, normalization.Permissions)
+				continue
+			}
+			if category, validationMessage, invalid := validationFailure(updateErr); invalid {
+				validationCategory = category
+				failures = append(failures, normalization.Role.Name+": "+validationMessage)
+			} else {
+				failures = append(failures, normalization.Role.Name+": "+updateErr.Error())
+			}
+		}
+
+		normalized := len(byOrg[org]) - len(failures)
+		switch {
+		case len(failures) == 1 && validationCategory != "":
+			results.FailedAs(validationCategory, org, failures[0], "Failed to normalize roles in %s (%d of %d updated): %s", org, normalized, len(byOrg[org]), failures[0])
+		case len(failures) > 0:
+			message := strings.Join(failures, "; ")
+			results.Failed(org, message, "Failed to normalize roles in %s (%d of %d updated): %s", org, normalized, len(byOrg[org]), message)
+		default:
+			message := fmt.Sprintf("Normalized %s", pluralize(normalized, "role", "roles"))
+			results.Succeeded(org, message, "%s in %s", message, org)
+		}
+	})
+
+	progressBar.Stop()
+
+	// Display summary
+	pterm.Println()
+	pterm.DefaultSection.Println("Summary")
+	results.printSummaryCounts("normalized")
+	if inventory.ErrorCount > 0 {
+		pterm.Error.Printfln("✗ Organizations that could not be read: %d", inventory.ErrorCount)
+	}
+
+	// Display command for replication
+	cmd := buildNormalizeReplicationCommand(opts)
+	printReplicationTip("these changes", cmd)
+
+	// Publish the run summary (GitHub Actions step summary, tracking issue)
+	details := []summaryDetail{
+		{Label: "Name Casing", Value: opts.nameCase},
+		{Label: "Roles to Normalize", Value: fmt.Sprintf("%d", len(plan))},
+		{Label: "Target Organizations", Value: fmt.Sprintf("%d", len(orgs))},
+		{Label: "Replication Command", Value: "`" + cmd + "`"},
+	}
+	if opts.roleName != "" {
+		details = append([]summaryDetail{{Label: "Role Name", Value: opts.roleName}}, details...)
+	}
+	publishRunSummary(opts, "Custom role normalization", details, results)
+
+	if err := results.Err(); err != nil {
+		return err
+	}
+	if inventory.ErrorCount > 0 {
+		return fmt.Errorf("completed with %d errors", inventory.ErrorCount)
+	}
+	return nil
+}
+
+// planNormalization returns the roles whose definition differs from its
+// normalized form: whitespace collapsed in the name and cased per nameCase,
+// the description trimmed, and duplicate permissions removed. With roleName,
+// only roles with that name are considered.
+func planNormalization(roles []orgRole, roleName, nameCase string) []roleNormalization {
+	common := commonRoleNames(roles)
+	namesByOrg := map[string]map[string]int64{}
+	for _, role := range roles {
+		if namesByOrg[role.Org] == nil {
+			namesByOrg[role.Org] = map[string]int64{}
+		}
+		namesByOrg[role.Org][roleNameKey(role.Role.Name)] = role.Role.ID
+	}
+
+	var plan []roleNormalization
+	for _, role := range roles {
+		current := role.Role
+		if roleName != "" && !sameRoleName(current.Name, roleName) {
+			continue
+		}
+
+		name := normalizeRoleName(current.Name, nameCase, common)
+		if id, taken := namesByOrg[role.Org][roleNameKey(name)]; taken && id != current.ID {
+			// Collapsing whitespace would collide with another role
+			name = current.Name
+		}
+		description := strings.TrimSpace(current.Description)
+		permissions := uniqueStrings(append([]string(nil), current.Permissions...))
+
+		changes := roleChanges(current, name, description, current.BaseRole, permissions)
+		if len(permissions) != len(current.Permissions) {
+			changes = append(changes, roleChange{
+				Field:   "Permissions",
+				Current: strings.Join(current.Permissions, ", "),
+				New:     "remove duplicates: " + strings.Join(duplicateStrings(current.Permissions), ", "),
+			})
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		plan = append(plan, roleNormalization{Org: role.Org, Role: current, Permissions: permissions, Changes: changes})
+	}
+
+	sort.SliceStable(plan, func(i, j int) bool {
+		if plan[i].Org != plan[j].Org {
+			return plan[i].Org < plan[j].Org
+		}
+		return plan[i].Role.Name < plan[j].Role.Name
+	})
+	return plan
+}
+
+// normalizeRoleName collapses runs of whitespace in a role name and applies
+// the casing convention. common maps each name key to its most used spelling.
+func normalizeRoleName(name, nameCase string, common map[string]string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	switch nameCase {
+	case nameCaseCommon:
+		if spelling, ok := common[roleNameKey(name)]; ok {
+			return spelling
+		}
+	case nameCaseTitle:
+		return cases.Title(language.Und, cases.NoLower).String(name)
+	case nameCaseLower:
+		return cases.Lower(language.Und).String(name)
+	case nameCaseUpper:
+		return cases.Upper(language.Und).String(name)
+	}
+	return name
+}
+
+// commonRoleNames returns the most used spelling of each role name across
+// organizations, keyed by roleNameKey. Ties go to the alphabetically first
+// spelling so every run picks the same one.
+func commonRoleNames(roles []orgRole) map[string]string {
+	counts := map[string]map[string]int{}
+	for _, role := range roles {
+		spelling := strings.Join(strings.Fields(role.Role.Name), " ")
+		key := roleNameKey(spelling)
+		if counts[key] == nil {
+			counts[key] = map[string]int{}
+		}
+		counts[key][spelling]++
+	}
+
+	common := map[string]string{}
+	for key, spellings := range counts {
+		best := ""
+		for spelling, count := range spellings {
+			if best == "" || count > spellings[best] || (count == spellings[best] && spelling < best) {
+				best = spelling
+			}
+		}
+		common[key] = best
+	}
+	return common
+}
+
+// duplicateStrings returns the values that appear more than once, in order of
+// their second appearance
+func duplicateStrings(values []string) []string {
+	seen := map[string]int{}
+	var duplicates []string
+	for _, value := range values {
+		seen[value]++
+		if seen[value] == 2 {
+			duplicates = append(duplicates, value)
+		}
+	}
+	return duplicates
+}
+
+func buildNormalizeReplicationCommand(opts options) string {
+	cmd := "gh custom-roles normalize"
+
+	if opts.hostname != "" {
+		cmd += " --hostname " + shellQuote(opts.hostname)
+	}
+	cmd += targetFlags(opts)
+	if opts.roleName != "" {
+		cmd += " --role-name " + shellQuote(opts.roleName)
+	}
+	if opts.nameCase != nameCaseKeep {
+		cmd += " --name-case " + opts.nameCase
+	}
+	cmd += pacingFlags(opts)
+
+	return cmd
+}
@@ -0,0 +1,409 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// orgInventory is what the extension can see of one organization
+type orgInventory struct {
+	Login string
+	Admin bool
+	// Plan is empty when the plan is not visible to the viewer
+	Plan string
+	// RoleCount is -1 when the custom roles could not be read
+	RoleCount int
+	RoleNames []string
+	Note      string
+}
+
+var orgsCmd = &cobra.Command{
+	Use:   "orgs",
+	Short: "Inspect the organizations that commands can target",
+}
+
+var orgsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the enterprise's organizations with admin access, plan, and custom role count",
+	Args:  cobra.NoArgs,
+	RunE:  runOrgsList,
+}
+
+var orgsVerifyCmd = &cobra.Command{
+	Use:   "verify [csv-file]",
+	Short: "Check that every organization in a CSV file or --orgs-from-cmd output can be targeted, without changing anything",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runOrgsVerify,
+}
+
+func init() {
+	// Orgs list command flags
+	orgsListCmd.Flags().StringVar(&opts.outputPath, "out", "", "Write the listed organizations to this CSV file, for use with --orgs-csv")
+	orgsListCmd.Flags().BoolVar(&opts.adminOnly, "admin-only", false, "Only list organizations the current account can administer")
+	orgsListCmd.Flags().BoolVar(&opts.missingAny, "missing-any", false, "Only list organizations that have no custom roles")
+	orgsListCmd.Flags().StringVar(&opts.missingRole, "missing-role", "", "Only list organizations that have no custom role with this name")
+	orgsListCmd.MarkFlagsMutuallyExclusive("missing-any", "missing-role")
+
+	orgsCmd.AddCommand(orgsListCmd)
+	orgsCmd.AddCommand(orgsVerifyCmd)
+}
+
+func runOrgsList(_ *cobra.Command, _ []string) error {
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	// Listing always reads the whole enterprise
+	opts.org, opts.orgsCSVPaths, opts.orgsFromCmd = "", nil, ""
+	opts.allOrgs = true
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, true); err != nil {
+		return err
+	}
+
+	if err := resolveEnterprise(); err != nil {
+		return err
+	}
+
+	// Validate concurrency and delay bounds
+	if err := validatePacing(opts); err != nil {
+		return err
+	}
+
+	pterm.Info.Println("Fetching organizations for enterprise...")
+	var logins []string
+	admin := map[string]bool{}
+	for page := range streamOrganizations(opts.hostname, opts.enterprise, newOrgFilter(opts)) {
+		if page.Err != nil {
+			return page.Err
+		}
+		logins = append(logins, page.Logins...)
+		for login, canAdminister := range page.Admin {
+			admin[login] = canAdminister
+		}
+	}
+	logins = shardOrganizations(opts, uniqueStrings(logins))
+	if len(logins) == 0 {
+		pterm.Info.Printfln("Enterprise %s has no organizations.", opts.enterprise)
+		return nil
+	}
+
+	if opts.adminOnly {
+		var administered []string
+		for _, login := range logins {
+			if admin[login] {
+				administered = append(administered, login)
+			}
+		}
+		logins = administered
+		if len(logins) == 0 {
+			pterm.Info.Printfln("The current account cannot administer any organization in %s.", opts.enterprise)
+			return nil
+		}
+	}
+
+	inventory, err := inspectOrganizations(opts, logins, func(org string) bool { return admin[org] })
+	if err != nil {
+		return err
+	}
+
+	title := "Organizations in " + opts.enterprise
+	var unchecked []orgInventory
+	if opts.missingAny || opts.missingRole != "" {
+		opts.missingRole = strings.TrimSpace(opts.missingRole)
+		var missing []orgInventory
+		missing, unchecked = missingRoles(inventory, opts.missingRole)
+		title = fmt.Sprintf("Organizations in %s without custom roles", opts.enterprise)
+		if opts.missingRole != "" {
+			title = fmt.Sprintf("Organizations in %s without the role %s", opts.enterprise, opts.missingRole)
+		}
+		if len(missing)+len(unchecked) == 0 {
+			pterm.Println()
+			pterm.Success.Printfln("None of the %d organizations is missing %s", len(inventory), missingRoleLabel(opts.missingRole))
+			return nil
+		}
+		// Organizations that could not be checked are listed with the reason,
+		// since new organizations often lack admin access for the account
+		inventory = append(missing, unchecked...)
+		sort.Slice(inventory, func(i, j int) bool { return inventory[i].Login < inventory[j].Login })
+	}
+
+	pterm.Println()
+	pterm.DefaultSection.Println(title)
+	data := pterm.TableData{{"Organization", "Admin", "Plan", "Custom roles", "Notes"}}
+	adminCount := 0
+	for _, org := range inventory {
+		if org.Admin {
+			adminCount++
+		}
+		data = append(data, orgInventoryRow(org))
+	}
+	if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+		return err
+	}
+	pterm.Println()
+	pterm.Info.Printfln("Organizations: %d", len(inventory))
+	pterm.Info.Printfln("With admin access: %d", adminCount)
+	if adminCount < len(inventory) {
+		pterm.Warning.Printfln("%d organizations cannot be changed with the current account", len(inventory)-adminCount)
+	}
+	if len(unchecked) > 0 {
+		pterm.Warning.Printfln("%d organizations could not be checked for %s; they are listed with the reason", len(unchecked), missingRoleLabel(opts.missingRole))
+	}
+
+	if opts.outputPath != "" {
+		if err := writeOrganizationsCSV(opts.outputPath, inventory); err != nil {
+			return err
+		}
+		pterm.Success.Printfln("Wrote %d organizations to %s", len(inventory), opts.outputPath)
+	}
+	return nil
+}
+
+// writeOrganizationsCSV writes one organization per row under an org header,
+// the format --orgs-csv reads back
+func writeOrganizationsCSV(path string, inventory []orgInventory) error {
+	file, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"org"}); err != nil {
+		return err
+	}
+	for _, org := range inventory {
+		if err := writer.Write([]string{org.Login}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func runOrgsVerify(_ *cobra.Command, args []string) error {
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		if opts.orgsFromCmd != "" {
+			return errors.New("pass either a CSV file or --orgs-from-cmd, not both")
+		}
+		opts.orgsCSVPaths = append(opts.orgsCSVPaths, args[0])
+	}
+	if len(opts.orgsCSVPaths) == 0 && opts.orgsFromCmd == "" {
+		path, err := promptText("Path to CSV file or directory of CSV files")
+		if err != nil {
+			return err
+		}
+		path = strings.TrimSpace(path)
+		if path == "" {
+			return errors.New("CSV file path is required")
+		}
+		opts.orgsCSVPaths = []string{path}
+	}
+	opts.org, opts.allOrgs = "", false
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, false); err != nil {
+		return err
+	}
+
+	orgs, err := resolveOrganizations(opts)
+	if err != nil {
+		return err
+	}
+	if len(orgs) == 0 {
+		return errors.New("no organizations provided")
+	}
+
+	// Validate concurrency and delay bounds
+	if err := validatePacing(opts); err != nil {
+		return err
+	}
+
+	inventory, err := inspectOrganizations(opts, orgs, nil)
+	if err != nil {
+		return err
+	}
+
+	pterm.Println()
+	source := strings.Join(opts.orgsCSVPaths, ", ")
+	if opts.orgsFromCmd != "" {
+		source = "the output of " + opts.orgsFromCmd
+	}
+	pterm.DefaultSection.Printfln("Organizations in %s", source)
+	data := pterm.TableData{{"Organization", "Admin", "Plan", "Custom roles", "Notes"}}
+	problems := 0
+	for _, org := range inventory {
+		if org.RoleCount < 0 {
+			problems++
+		}
+		data = append(data, orgInventoryRow(org))
+	}
+	if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+		return err
+	}
+
+	pterm.Println()
+	pterm.Info.Printfln("✓ Ready: %d", len(inventory)-problems)
+	if problems > 0 {
+		pterm.Error.Printfln("✗ Cannot be targeted: %d", problems)
+		return fmt.Errorf("%d of %d organizations cannot be targeted", problems, len(inventory))
+	}
+	pterm.Success.Println("Every organization can be targeted.")
+	return nil
+}
+
+// inspectOrganizations reads the plan and custom role count of each
+// organization. admin reports whether the viewer can administer an
+// organization; organizations where it returns false are not queried for
+// roles, which would fail anyway. With a nil admin, access is probed instead:
+// the organization must exist and its custom roles must be readable.
+func inspectOrganizations(opts options, orgs []string, admin func(string) bool) ([]orgInventory, error) {
+	progressBar, err := startProgressbar(len(orgs), "Inspecting organizations")
+	if err != nil {
+		return nil, err
+	}
+	defer progressBar.Stop()
+
+	var mu sync.Mutex
+	var inventory []orgInventory
+	processTargets(opts, orgs, func(org string) {
+		entry := inspectOrganization(opts.hostname, org, admin)
+
+		mu.Lock()
+		inventory = append(inventory, entry)
+		mu.Unlock()
+		progressBar.Increment()
+	})
+	progressBar.Stop()
+
+	sort.Slice(inventory, func(i, j int) bool { return inventory[i].Login < inventory[j].Login })
+	return inventory, nil
+}
+
+func inspectOrganization(hostname, org string, admin func(string) bool) orgInventory {
+	entry := orgInventory{Login: org, RoleCount: -1}
+	if admin != nil && !admin(org) {
+		entry.Note = "No admin access"
+		return entry
+	}
+
+	plan, planErr := fetchOrganizationPlan(hostname, org)
+	if isNotFoundError(planErr) {
+		entry.Note = "Organization not found"
+		return entry
+	}
+	entry.Plan = plan
+
+	roles, listErr := listCustomRoles(hostname, org)
+	entry.Admin = listErr == nil || isPlanUnsupportedError(listErr)
+	if admin != nil {
+		entry.Admin = true
+	}
+	switch {
+	case isPlanUnsupportedError(listErr):
+		entry.Note = "Custom roles not available on this plan"
+	case isForbiddenError(listErr):
+		entry.Note = "No admin access"
+	case listErr != nil:
+		entry.Note = redactSecrets(listErr.Error())
+	default:
+		entry.RoleCount = len(roles)
+		for _, role := range roles {
+			entry.RoleNames = append(entry.RoleNames, role.Name)
+		}
+	}
+	return entry
+}
+
+// missingRoles keeps the organizations with no custom roles, or with no role
+// named roleName when it is set. Organizations whose roles could not be read
+// are returned separately, since they may or may not have the role.
+func missingRoles(inventory []orgInventory, roleName string) (missing, unchecked []orgInventory) {
+	for _, org := range inventory {
+		if org.RoleCount < 0 {
+			unchecked = append(unchecked, org)
+			continue
+		}
+		found := false
+		for _, name := range org.RoleNames {
+			if roleName == "" || sameRoleName(name, roleName) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, org)
+		}
+	}
+	return missing, unchecked
+}
+
+// missingRoleLabel names what --missing-any or --missing-role looks for, for
+// messages
+func missingRoleLabel(roleName string) string {
+	if roleName == "" {
+		return "custom roles"
+	}
+	return "the role " + roleName
+}
+
+// fetchOrganizationPlan returns the name of an organization's plan, which
+// the API only reveals to organization owners
+func fetchOrganizationPlan(hostname, org string) (string, error) {
+	response, stderr, err := ghAPI(hostname, "orgs/"+org)
+	if err != nil {
+		return "", fmt.Errorf("organization lookup failed: %w (%s)", err, stderr.String())
+	}
+
+	var payload struct {
+		Plan *struct {
+			Name string `json:"name"`
+		} `json:"plan"`
+	}
+	if err := json.Unmarshal(response.Bytes(), &payload); err != nil {
+		return "", err
+	}
+	if payload.Plan == nil {
+		return "", errors.New("plan not visible")
+	}
+	return strings.ToLower(payload.Plan.Name), nil
+}
+
+func orgInventoryRow(org orgInventory) []string {
+	adminText := "no"
+	if org.Admin {
+		adminText = "yes"
+	}
+	plan := org.Plan
+	if plan == "" {
+		plan = "-"
+	}
+	roleCount := "-"
+	if org.RoleCount >= 0 {
+		roleCount = fmt.Sprintf("%d", org.RoleCount)
+	}
+	return []string{org.Login, adminText, plan, roleCount, org.Note}
+}
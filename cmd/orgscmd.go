@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/pterm/pterm"
+)
+
+// loadOrganizationsFromCommand runs command through the shell and reads one
+// organization per line from its stdout, so inventories kept in a CMDB can be
+// targeted without an intermediate file. Blank lines and lines starting with
+// # are ignored, and invalid names are reported with their line number and
+// skipped. The command's stderr is shown as is, and GH_CUSTOM_ROLES_HOSTNAME
+// tells it which host the organizations are for.
+func loadOrganizationsFromCommand(command string) ([]string, error) {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	inventoryCmd := exec.Command(shell, flag, command)
+	var stdout bytes.Buffer
+	inventoryCmd.Env = append(os.Environ(), "GH_CUSTOM_ROLES_HOSTNAME="+opts.hostname)
+	inventoryCmd.Stdout = &stdout
+	inventoryCmd.Stderr = os.Stderr
+	if err := inventoryCmd.Run(); err != nil {
+		return nil, fmt.Errorf("--orgs-from-cmd %s failed: %w", shellQuote(command), err)
+	}
+
+	orgSet := map[string]bool{}
+	var orgs []string
+	scanner := bufio.NewScanner(&stdout)
+	for line := 1; scanner.Scan(); line++ {
+		value := strings.TrimSpace(scanner.Text())
+		if value == "" || strings.HasPrefix(value, "#") {
+			continue
+		}
+		org := normalizeOrg(value)
+		if !orgLoginPattern.MatchString(org) || len(org) > 39 {
+			pterm.Warning.Printfln("--orgs-from-cmd line %d: skipping %q (not a valid organization name)", line, value)
+			continue
+		}
+		if !orgSet[org] {
+			orgSet[org] = true
+			orgs = append(orgs, org)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	pterm.Info.Printfln("Read %d organizations from --orgs-from-cmd", len(orgs))
+	return orgs, nil
+}
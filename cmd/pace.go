@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// Pacing profiles accepted by --profile-pace
+const (
+	paceGentle     = "gentle"
+	paceNormal     = "normal"
+	paceAggressive = "aggressive"
+)
+
+var paceProfileNames = []string{paceGentle, paceNormal, paceAggressive}
+
+// paceSettings are the pacing, retry, and failure handling values a profile
+// sets
+type paceSettings struct {
+	concurrency int
+	delay       int
+	requestRate float64
+	maxFailures string
+	retries     int
+	jitter      float64
+}
+
+// paceProfiles holds the settings of each profile for GitHub Enterprise
+// Server and for GitHub.com and GHE.com. A server's rate limits are set by
+// its administrators and it shares capacity with every other user, so its
+// profiles are more conservative than their cloud counterparts.
+var paceProfiles = map[string]struct{ server, cloud paceSettings }{
+	paceGentle: {
+		server: paceSettings{concurrency: 1, delay: 2, requestRate: 2, maxFailures: "3", retries: 5, jitter: 2},
+		cloud:  paceSettings{concurrency: 1, delay: 1, requestRate: 5, maxFailures: "5", retries: 5, jitter: 1},
+	},
+	paceNormal: {
+		server: paceSettings{concurrency: 3, requestRate: 5, maxFailures: "5%", retries: 3, jitter: 1},
+		cloud:  paceSettings{concurrency: 5, requestRate: 10, maxFailures: "5%", retries: 3, jitter: 0.5},
+	},
+	paceAggressive: {
+		server: paceSettings{concurrency: 8, requestRate: 10, maxFailures: "10%", retries: 2, jitter: 0.5},
+		cloud:  paceSettings{concurrency: 15, requestRate: 20, maxFailures: "10%", retries: 2, jitter: 0.25},
+	},
+}
+
+// pacingFlagNames are the flags a pacing profile sets. Flags passed
+// explicitly take precedence over the profile.
+var pacingFlagNames = []string{"concurrency", "delay", "requests-per-second", "max-failures", "retries", "jitter"}
+
+// explicitPacing records which pacing flags were passed on the command line
+var explicitPacing = map[string]bool{}
+
+// validatePaceProfile checks --profile-pace and records the pacing flags the
+// profile must not override
+func validatePaceProfile(cmd *cobra.Command) error {
+	if opts.paceProfile == "" {
+		return nil
+	}
+	opts.paceProfile = strings.ToLower(strings.TrimSpace(opts.paceProfile))
+	if !slices.Contains(paceProfileNames, opts.paceProfile) {
+		return fmt.Errorf("invalid --profile-pace %q: expected %s", opts.paceProfile, strings.Join(paceProfileNames, ", "))
+	}
+	for _, name := range pacingFlagNames {
+		explicitPacing[name] = cmd.Flags().Changed(name)
+	}
+	// --fail-fast replaces the profile's failure threshold
+	explicitPacing["max-failures"] = explicitPacing["max-failures"] || cmd.Flags().Changed("fail-fast")
+	return nil
+}
+
+// paceProfileSettings returns the settings of profile tuned for hostname
+func paceProfileSettings(profile, hostname string) paceSettings {
+	if hostType(hostname) == hostTypeServer {
+		return paceProfiles[profile].server
+	}
+	return paceProfiles[profile].cloud
+}
+
+// applyPaceProfile sets the pacing options from --profile-pace once the host
+// is known. A profile with a delay processes targets sequentially, so it
+// leaves an explicit --concurrency alone, and vice versa.
+func applyPaceProfile(hostname string) {
+	if opts.paceProfile == "" {
+		return
+	}
+	settings := paceProfileSettings(opts.paceProfile, hostname)
+	if !explicitPacing["delay"] && !explicitPacing["concurrency"] {
+		opts.concurrency = settings.concurrency
+		opts.delay = settings.delay
+	}
+	if !explicitPacing["requests-per-second"] {
+		opts.requestRate = settings.requestRate
+	}
+	if !explicitPacing["max-failures"] {
+		opts.maxFailures = settings.maxFailures
+	}
+	if !explicitPacing["retries"] {
+		opts.retries = settings.retries
+	}
+	if !explicitPacing["jitter"] {
+		opts.jitter = settings.jitter
+	}
+
+	pacing := fmt.Sprintf("concurrency %d", opts.concurrency)
+	if opts.delay > 0 {
+		pacing = fmt.Sprintf("%ds delay between targets", opts.delay)
+	}
+	failures := "no failure limit"
+	if opts.failFast {
+		failures = "fail fast"
+	} else if opts.maxFailures != "" {
+		failures = "max failures " + opts.maxFailures
+	}
+	pterm.Info.Printfln("Pacing profile %s for %s: %s, %g requests/second, %d retries, up to %gs jitter, %s",
+		opts.paceProfile, hostType(hostname), pacing, opts.requestRate, opts.retries, opts.jitter, failures)
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// rolePatchPaths maps the fields of a role change to their JSON Pointer in
+// the role as the REST API returns it
+var rolePatchPaths = map[string]string{
+	"Name":        "/name",
+	"Description": "/description",
+	"Base Role":   "/base_role",
+	"Permissions": "/permissions",
+}
+
+// patchOperation is one RFC 6902 JSON Patch operation
+type patchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// roleUpdate is the exact change a run applied to one role, saved in the run
+// record with --record-changes
+type roleUpdate struct {
+	// Host is set in multi-host runs
+	Host   string `json:"host,omitempty"`
+	Target string `json:"target"`
+	RoleID int64  `json:"role_id"`
+	Role   string `json:"role"`
+	// Patch turns the role as it was into the role as it was left. Each
+	// replace is preceded by a test of the value it replaced, so the patch
+	// fails when applied to any other version of the role.
+	Patch []patchOperation `json:"patch"`
+}
+
+// rolePatch converts role changes into a JSON Patch against the current
+// role. Permissions are replaced as a whole list, since the API sets them
+// that way.
+func rolePatch(current customRole, changes []roleChange, permissions []string) []patchOperation {
+	patch := make([]patchOperation, 0, 2*len(changes))
+	for _, change := range changes {
+		var before, after any = change.Current, change.New
+		if change.Field == "Permissions" {
+			before = append([]string{}, current.Permissions...)
+			after = append([]string{}, permissions...)
+		}
+		path := rolePatchPaths[change.Field]
+		patch = append(patch,
+			patchOperation{Op: "test", Path: path, Value: before},
+			patchOperation{Op: "replace", Path: path, Value: after},
+		)
+	}
+	return patch
+}
+
+// String describes the replaced values on one line, such as
+// "/base_role: "write" → "maintain""
+func (u roleUpdate) String() string {
+	var parts []string
+	for i, operation := range u.Patch {
+		if operation.Op != "replace" {
+			continue
+		}
+		before := "?"
+		if i > 0 && u.Patch[i-1].Op == "test" && u.Patch[i-1].Path == operation.Path {
+			before = patchValue(u.Patch[i-1].Value)
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s → %s", operation.Path, before, patchValue(operation.Value)))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func patchValue(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/config"
+)
+
+// permissionCacheTTL is how long a host's fine-grained permission catalog
+// is reused from disk before it is fetched again
+const permissionCacheTTL = 24 * time.Hour
+
+// cachedPermissions is the on-disk copy of a host's permission catalog
+type cachedPermissions struct {
+	Hostname    string                  `json:"hostname"`
+	FetchedAt   time.Time               `json:"fetched_at"`
+	Permissions []fineGrainedPermission `json:"permissions"`
+}
+
+func permissionCachePath(hostname string) string {
+	name := strings.NewReplacer("/", "_", ":", "_").Replace(normalizeHostname(hostname))
+	return filepath.Join(config.CacheDir(), "gh-custom-roles", "permissions", name+".json")
+}
+
+// loadCachedPermissions returns the catalog saved for hostname if it is
+// younger than permissionCacheTTL. --refresh-permissions ignores it.
+func loadCachedPermissions(hostname string) ([]fineGrainedPermission, bool) {
+	if opts.refreshPermissions {
+		return nil, false
+	}
+	data, err := os.ReadFile(permissionCachePath(hostname))
+	if err != nil {
+		return nil, false
+	}
+	var cached cachedPermissions
+	if err := json.Unmarshal(data, &cached); err != nil || len(cached.Permissions) == 0 {
+		return nil, false
+	}
+	if time.Since(cached.FetchedAt) > permissionCacheTTL {
+		return nil, false
+	}
+	return cached.Permissions, true
+}
+
+// saveCachedPermissions stores the catalog fetched for hostname. Failures
+// are ignored since the cache only saves requests.
+func saveCachedPermissions(hostname string, permissions []fineGrainedPermission) {
+	data, err := json.MarshalIndent(cachedPermissions{
+		Hostname:    normalizeHostname(hostname),
+		FetchedAt:   time.Now().UTC(),
+		Permissions: permissions,
+	}, "", "  ")
+	if err != nil {
+		return
+	}
+	path := permissionCachePath(hostname)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// permissionCategory groups fine-grained permissions by the feature they
+// control. A permission belongs to the first category with a keyword in its
+// name; keywords are matched against the name wrapped in underscores.
+type permissionCategory struct {
+	Name     string
+	Keywords []string
+}
+
+// permissionCategories are checked in order, so security permissions on
+// issues or pull requests stay under security
+var permissionCategories = []permissionCategory{
+	{Name: "security", Keywords: []string{"_scanning_", "_dependabot_", "_secret_", "_security_"}},
+	{Name: "discussions", Keywords: []string{"_discussion_", "_discussions_"}},
+	{Name: "pull-requests", Keywords: []string{"_pull_request_", "_pr_", "_merge_queue_", "_protected_branch_", "_branch_protection_"}},
+	{Name: "issues", Keywords: []string{"_issue_", "_issues_", "_assignee_", "_label_", "_milestone_", "_duplicate_"}},
+	{Name: "settings", Keywords: []string{"_settings_", "_webhooks_", "_deploy_keys_", "_repo_", "_social_preview_", "_interaction_limits_", "_environments_"}},
+}
+
+// otherPermissionCategory holds permissions that match no category
+const otherPermissionCategory = "other"
+
+var permissionsCmd = &cobra.Command{
+	Use:   "permissions",
+	Short: "List and search the fine-grained permissions custom roles can grant",
+	Example: `  gh custom-roles permissions --org myorg --search secret
+  gh custom-roles permissions --org myorg --category security`,
+	Args: cobra.NoArgs,
+	RunE: runPermissions,
+}
+
+func init() {
+	// Permissions command flags
+	permissionsCmd.Flags().StringVar(&opts.permissionSearch, "search", "", "Only list permissions whose name or description contains every word")
+	permissionsCmd.Flags().StringVar(&opts.permissionCategory, "category", "", "Only list permissions in this category: "+strings.Join(permissionCategoryNames(), ", "))
+	_ = permissionsCmd.RegisterFlagCompletionFunc("category", cobra.FixedCompletions(permissionCategoryNames(), cobra.ShellCompDirectiveNoFileComp))
+}
+
+func runPermissions(_ *cobra.Command, _ []string) error {
+	var err error
+	category := strings.ToLower(strings.TrimSpace(opts.permissionCategory))
+	if category != "" && !slices.Contains(permissionCategoryNames(), category) {
+		return fmt.Errorf("invalid category %q: expected one of %s", opts.permissionCategory, strings.Join(permissionCategoryNames(), ", "))
+	}
+
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	if opts.allOrgs || len(opts.orgsCSVPaths) > 0 || opts.orgsFromCmd != "" {
+		return errors.New("permissions reads the catalog of a single organization; use --org")
+	}
+	if opts.org == "" {
+		opts.org, err = promptText("Organization name")
+		if err != nil {
+			return err
+		}
+	}
+	org := normalizeOrg(opts.org)
+	if org == "" {
+		return errors.New("organization name is required")
+	}
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, false); err != nil {
+		return err
+	}
+
+	catalog, err := listFineGrainedPermissions(opts.hostname, org)
+	if err != nil {
+		return err
+	}
+	matches := filterPermissions(catalog, opts.permissionSearch, category)
+	if len(matches) == 0 {
+		pterm.Info.Printfln("No permissions match (%d in the catalog).", len(catalog))
+		return nil
+	}
+
+	data := pterm.TableData{{"Permission", "Category", "Description"}}
+	for _, permission := range matches {
+		data = append(data, []string{permission.Name, categorizePermission(permission.Name), permission.Description})
+	}
+	if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+		return err
+	}
+	pterm.Println()
+	pterm.Info.Printfln("%d of %d permissions", len(matches), len(catalog))
+	return nil
+}
+
+// filterPermissions returns the catalog permissions in category (any when
+// empty) whose name or description contains every word of search, ignoring
+// case and treating underscores in names as spaces. Results are sorted by
+// name.
+func filterPermissions(catalog []fineGrainedPermission, search, category string) []fineGrainedPermission {
+	words := strings.Fields(strings.ToLower(strings.ReplaceAll(search, "_", " ")))
+	var matches []fineGrainedPermission
+	for _, permission := range catalog {
+		if category != "" && categorizePermission(permission.Name) != category {
+			continue
+		}
+		text := strings.ToLower(strings.ReplaceAll(permission.Name, "_", " ") + " " + permission.Description)
+		if !allWordsIn(text, words) {
+			continue
+		}
+		matches = append(matches, permission)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Name < matches[j].Name
+	})
+	return matches
+}
+
+func allWordsIn(text string, words []string) bool {
+	for _, word := range words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// categorizePermission returns the category of a fine-grained permission
+func categorizePermission(name string) string {
+	wrapped := "_" + strings.ToLower(name) + "_"
+	for _, category := range permissionCategories {
+		for _, keyword := range category.Keywords {
+			if strings.Contains(wrapped, keyword) {
+				return category.Name
+			}
+		}
+	}
+	return otherPermissionCategory
+}
+
+// permissionCategoryNames lists the categories accepted by --category
+func permissionCategoryNames() []string {
+	names := make([]string, 0, len(permissionCategories)+1)
+	for _, category := range permissionCategories {
+		names = append(names, category.Name)
+	}
+	return append(names, otherPermissionCategory)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCategorizePermission(t *testing.T) {
+	tests := map[string]string{
+		"view_secret_scanning_alerts":     "security",
+		"resolve_dependabot_alerts":       "security",
+		"delete_alerts_code_scanning":     "security",
+		"close_discussion":                "discussions",
+		"convert_issues_to_discussions":   "discussions",
+		"request_pr_review":               "pull-requests",
+		"bypass_branch_protection":        "pull-requests",
+		"close_issue":                     "issues",
+		"add_label":                       "issues",
+		"manage_webhooks":                 "settings",
+		"edit_repo_metadata":              "settings",
+		"jump_merge_queue":                "pull-requests",
+		"set_social_preview":              "settings",
+		"some_future_permission":          "other",
+		"VIEW_SECRET_SCANNING_ALERTS":     "security",
+		"edit_repo_custom_properties_val": "settings",
+	}
+	for name, want := range tests {
+		if got := categorizePermission(name); got != want {
+			t.Errorf("categorizePermission(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestFilterPermissions(t *testing.T) {
+	catalog := []fineGrainedPermission{
+		{Name: "view_secret_scanning_alerts", Description: "View secret scanning alerts"},
+		{Name: "resolve_secret_scanning_alerts", Description: "Resolve secret scanning alerts"},
+		{Name: "close_issue", Description: "Close an issue"},
+		{Name: "manage_webhooks", Description: "Manage webhooks, which can receive secrets"},
+	}
+	tests := []struct {
+		name     string
+		search   string
+		category string
+		want     []string
+	}{
+		{name: "everything", want: []string{"close_issue", "manage_webhooks", "resolve_secret_scanning_alerts", "view_secret_scanning_alerts"}},
+		{name: "name and description", search: "secret", want: []string{"manage_webhooks", "resolve_secret_scanning_alerts", "view_secret_scanning_alerts"}},
+		{name: "every word", search: "resolve SECRET", want: []string{"resolve_secret_scanning_alerts"}},
+		{name: "underscores", search: "secret_scanning", want: []string{"resolve_secret_scanning_alerts", "view_secret_scanning_alerts"}},
+		{name: "category", search: "secret", category: "security", want: []string{"resolve_secret_scanning_alerts", "view_secret_scanning_alerts"}},
+		{name: "no match", search: "deploy", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, permission := range filterPermissions(catalog, tt.search, tt.category) {
+				got = append(got, permission.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("filterPermissions(%q, %q) = %v, want %v", tt.search, tt.category, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pterm/pterm"
+)
+
+// printPermissionSuggestions shows the catalog permissions closest to the
+// unknown names passed with --permissions, so the catalog can be checked
+// without switching to interactive mode. Permissions are grouped by their
+// category, the words after the leading verb (issue in close_issue), and the
+// categories sharing the most words with what was typed are shown. When
+// nothing is close, the whole catalog is shown.
+func printPermissionSuggestions(unknown []string, catalog []fineGrainedPermission) {
+	suggestions := closestPermissions(unknown, catalog)
+	if len(suggestions) == 0 {
+		suggestions = catalog
+		pterm.Info.Println("Available permissions:")
+	} else {
+		pterm.Info.Println("Closest available permissions:")
+	}
+
+	data := pterm.TableData{{"Permission", "Description"}}
+	for _, permission := range suggestions {
+		data = append(data, []string{permission.Name, permission.Description})
+	}
+	_ = pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+}
+
+// closestPermissions returns the catalog permissions whose category shares
+// the most words with any of the unknown names, sorted by name
+func closestPermissions(unknown []string, catalog []fineGrainedPermission) []fineGrainedPermission {
+	scores := make([]int, len(catalog))
+	best := 0
+	for _, name := range unknown {
+		typed := permissionWords(name)
+		for i, permission := range catalog {
+			words := permissionWords(permission.Name)
+			if len(words) > 1 {
+				words = words[1:]
+			}
+			score := 0
+			for _, word := range words {
+				for _, candidate := range typed {
+					if similarWords(word, candidate) {
+						score++
+						break
+					}
+				}
+			}
+			scores[i] = max(scores[i], score)
+			best = max(best, score)
+		}
+	}
+	if best == 0 {
+		return nil
+	}
+
+	var closest []fineGrainedPermission
+	for i, permission := range catalog {
+		if scores[i] == best {
+			closest = append(closest, permission)
+		}
+	}
+	sort.SliceStable(closest, func(i, j int) bool {
+		return closest[i].Name < closest[j].Name
+	})
+	return closest
+}
+
+// permissionWords splits a permission name into lowercase words, with a
+// trailing plural s removed so issues matches issue
+func permissionWords(name string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '_' || r == '-' || r == ' '
+	})
+	for i, field := range fields {
+		if len(field) > 3 {
+			fields[i] = strings.TrimSuffix(field, "s")
+		}
+	}
+	return fields
+}
+
+// similarWords reports whether a and b are the same word or, for words of
+// four letters or more, a likely typo of each other
+func similarWords(a, b string) bool {
+	if a == b {
+		return true
+	}
+	shorter := min(len(a), len(b))
+	if shorter < 4 {
+		return false
+	}
+	limit := 1
+	if shorter >= 5 {
+		// Allows a swapped pair of letters, as in lable
+		limit = 2
+	}
+	return editDistance(a, b) <= limit
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
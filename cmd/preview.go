@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pterm/pterm"
+)
+
+// previewSampleSize is the number of organizations listed per preview category
+const previewSampleSize = 5
+
+// precheckConcurrency is the minimum number of parallel existence checks.
+// Reads are cheap, so the pre-check does not wait for --delay.
+const precheckConcurrency = 10
+
+// roleCheck is the result of checking every target organization for an
+// existing role before creation
+type roleCheck struct {
+	Create       []string
+	Exists       []string
+	Inaccessible []string
+	// AtQuota is only filled with --skip-at-quota
+	AtQuota    []string
+	Errors     map[string]error
+	RoleCounts map[string]int
+	// Existing holds the current definition of each role in Exists
+	Existing map[string]customRole
+	// Duplicates holds, for organizations in Create, an existing role with
+	// the requested base role and permissions under a different name
+	Duplicates map[string]customRole
+}
+
+// precheckRoles checks every target organization for an existing role in a
+// concurrent pass, so the creation pass only has to visit organizations that
+// need the role. Organizations that would get the role are also checked for
+// a role that already grants baseRole and permissions under another name.
+func precheckRoles(opts options, orgs []string, baseRole string, permissions []string) (roleCheck, error) {
+	check := roleCheck{Errors: map[string]error{}, RoleCounts: map[string]int{}, Existing: map[string]customRole{}, Duplicates: map[string]customRole{}}
+
+	progressBar, err := startProgressbar(len(orgs), "Checking target organizations")
+	if err != nil {
+		return check, err
+	}
+	defer progressBar.Stop()
+
+	var mu sync.Mutex
+	processTargets(precheckOptions(opts), orgs, func(org string) {
+		emitEvent(streamEvent{Event: eventChecking, Target: org})
+		roles, listErr := listCustomRoles(opts.hostname, org)
+		existing := matchRole(roles, opts.roleName)
+		mu.Lock()
+		defer mu.Unlock()
+		check.RoleCounts[org] = len(roles)
+		switch {
+		case listErr != nil:
+			check.Inaccessible = append(check.Inaccessible, org)
+			check.Errors[org] = listErr
+		case existing != nil:
+			check.Exists = append(check.Exists, org)
+			check.Existing[org] = *existing
+		case opts.skipAtQuota && len(roles) >= opts.roleQuota:
+			check.AtQuota = append(check.AtQuota, org)
+		default:
+			check.Create = append(check.Create, org)
+			if duplicate := duplicateRole(roles, opts.roleName, baseRole, permissions); duplicate != nil {
+				check.Duplicates[org] = *duplicate
+			}
+		}
+		progressBar.Increment()
+	})
+	progressBar.Stop()
+
+	sort.Strings(check.Create)
+	sort.Strings(check.Exists)
+	sort.Strings(check.Inaccessible)
+	sort.Strings(check.AtQuota)
+	return check, nil
+}
+
+// preflightRoles runs the pre-check of every create and prints what it found
+// before the confirmation prompt: the full breakdown with --preview, and
+// otherwise only organizations where the role would duplicate an existing one
+func preflightRoles(opts options, orgs []string, baseRole string, permissions []string) (roleCheck, error) {
+	check, err := precheckRoles(opts, orgs, baseRole, permissions)
+	if err != nil {
+		return check, err
+	}
+	switch {
+	case opts.preview:
+		printRoleCheck(check)
+	case len(check.Duplicates) > 0:
+		printDuplicateRoles(check)
+	default:
+		return check, nil
+	}
+	pterm.Println()
+	return check, nil
+}
+
+// precheckOptions returns the pacing used for existence checks
+func precheckOptions(opts options) options {
+	checkOpts := opts
+	checkOpts.delay = 0
+	if checkOpts.concurrency < precheckConcurrency {
+		checkOpts.concurrency = precheckConcurrency
+	}
+	return checkOpts
+}
+
+func printRoleCheck(check roleCheck) {
+	pterm.Info.Printfln("Will create: %d%s", len(check.Create), previewSample(check.Create))
+	if len(check.Exists) > 0 && opts.force {
+		pterm.Warning.Printfln("Will update if outdated (role exists): %d%s", len(check.Exists), previewSample(check.Exists))
+	} else if len(check.Exists) > 0 {
+		pterm.Warning.Printfln("Will skip (role exists): %d%s", len(check.Exists), previewSample(check.Exists))
+	}
+	if len(check.AtQuota) > 0 {
+		pterm.Warning.Printfln("Will skip (custom role quota reached): %d%s", len(check.AtQuota), previewSample(check.AtQuota))
+	}
+	if len(check.Duplicates) > 0 {
+		printDuplicateRoles(check)
+	}
+	if len(check.Inaccessible) > 0 {
+		pterm.Warning.Printfln("Inaccessible: %d%s", len(check.Inaccessible), previewSample(check.Inaccessible))
+	}
+}
+
+// printDuplicateRoles warns about organizations that already have a role with
+// the requested base role and permissions under a different name
+func printDuplicateRoles(check roleCheck) {
+	pterm.Warning.Printfln("Same base role and permissions as an existing role: %d%s", len(check.Duplicates), previewSample(describeDuplicates(check.Duplicates)))
+}
+
+func previewSample(orgs []string) string {
+	if len(orgs) == 0 {
+		return ""
+	}
+	if len(orgs) <= previewSampleSize {
+		return " (" + strings.Join(orgs, ", ") + ")"
+	}
+	return " (" + strings.Join(orgs[:previewSampleSize], ", ") + ", ...)"
+}
+
+// describeDuplicates lists duplicate roles as "org (role name)", sorted by
+// organization
+func describeDuplicates(duplicates map[string]customRole) []string {
+	described := make([]string, 0, len(duplicates))
+	for org, role := range duplicates {
+		described = append(described, fmt.Sprintf("%s (%s)", org, role.Name))
+	}
+	sort.Strings(described)
+	return described
+}
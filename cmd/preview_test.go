@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/pterm/pterm"
+)
+
+// fakeHost serves the REST API of a test host from handler and returns its
+// hostname
+func fakeHost(t *testing.T, handler http.Handler) string {
+	t.Helper()
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+
+	hostname := strings.TrimPrefix(server.URL, "https://")
+	client, err := api.NewRESTClient(api.ClientOptions{
+		Host:      hostname,
+		AuthToken: "test-token",
+		Transport: server.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiClientsMu.Lock()
+	restClients[hostname] = client
+	apiClientsMu.Unlock()
+	t.Cleanup(func() { resetClients(hostname) })
+	return hostname
+}
+
+func TestPreflightRolesWarnsAboutDuplicates(t *testing.T) {
+	rolesByOrg := map[string]string{
+		"acme-web":  `{"total_count": 1, "custom_roles": [{"id": 1, "name": "Dev", "base_role": "write", "permissions": ["delete_alerts_code_scanning"]}]}`,
+		"acme-data": `{"total_count": 1, "custom_roles": [{"id": 2, "name": "Reader", "base_role": "read", "permissions": []}]}`,
+	}
+	hostname := fakeHost(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for org, body := range rolesByOrg {
+			if r.URL.Path == fmt.Sprintf("/api/v3/orgs/%s/custom-repository-roles", org) {
+				_, _ = w.Write([]byte(body))
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}))
+
+	var output bytes.Buffer
+	pterm.SetDefaultOutput(&output)
+	accessible = true
+	t.Cleanup(func() {
+		pterm.SetDefaultOutput(os.Stdout)
+		accessible = false
+	})
+
+	// The default path, without --preview
+	checkOpts := options{hostname: hostname, roleName: "Developer", concurrency: 1, roleQuota: 3}
+	check, err := preflightRoles(checkOpts, []string{"acme-web", "acme-data"}, "write", []string{"delete_alerts_code_scanning"})
+	if err != nil {
+		t.Fatalf("preflightRoles: %v", err)
+	}
+
+	if duplicate, ok := check.Duplicates["acme-web"]; !ok || duplicate.Name != "Dev" {
+		t.Errorf("Duplicates = %v, want acme-web (Dev)", check.Duplicates)
+	}
+	if _, ok := check.Duplicates["acme-data"]; ok {
+		t.Errorf("acme-data reported as a duplicate: %v", check.Duplicates)
+	}
+	if !strings.Contains(output.String(), "Same base role and permissions as an existing role: 1 (acme-web (Dev))") {
+		t.Errorf("no duplicate warning printed:\n%s", output.String())
+	}
+	if strings.Contains(output.String(), "Will create") {
+		t.Errorf("full pre-check breakdown printed without --preview:\n%s", output.String())
+	}
+}
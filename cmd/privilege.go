@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+)
+
+// categoryPrivilegeIncrease is the failure category for role updates blocked
+// by --base-role-upgrade-guard
+const categoryPrivilegeIncrease = "Privilege increase blocked"
+
+// sensitivePermissions are the fine-grained permissions that weaken branch
+// protections, hide security findings, or expose secrets and integrations.
+// Adding one to an existing role is a privilege increase for the guard.
+var sensitivePermissions = []string{
+	"bypass_branch_protection",
+	"create_solo_merge_queue_entry",
+	"delete_alerts_code_scanning",
+	"edit_repo_protections",
+	"jump_merge_queue",
+	"manage_deploy_keys",
+	"manage_webhooks",
+	"resolve_dependabot_alerts",
+	"resolve_secret_scanning_alerts",
+	"view_secret_scanning_alerts",
+}
+
+// privilegeIncrease describes how updating a role to baseRole and permissions
+// raises the access it grants, such as
+// "base role write → maintain, adds bypass_branch_protection", or returns ""
+// when the update does not
+func privilegeIncrease(current customRole, baseRole string, permissions []string) string {
+	var increases []string
+	if baseRoleRank[baseRole] > baseRoleRank[current.BaseRole] {
+		increases = append(increases, "base role "+current.BaseRole+" → "+baseRole)
+	}
+	var added []string
+	for _, permission := range permissions {
+		if slices.Contains(sensitivePermissions, permission) && !slices.Contains(current.Permissions, permission) {
+			added = append(added, permission)
+		}
+	}
+	if len(added) > 0 {
+		increases = append(increases, "adds "+strings.Join(added, ", "))
+	}
+	return strings.Join(increases, ", ")
+}
+
+// blockedPrivilegeIncrease returns the privilege increase of an update that
+// --base-role-upgrade-guard blocks, or "" when the update may proceed
+func blockedPrivilegeIncrease(current customRole, baseRole string, permissions []string) string {
+	if !opts.upgradeGuard || opts.allowPrivilegeIncrease {
+		return ""
+	}
+	return privilegeIncrease(current, baseRole, permissions)
+}
+
+// privilegeGuardFlags returns the replication command flags for the
+// privilege increase guard
+func privilegeGuardFlags(opts options) string {
+	var flags string
+	if opts.upgradeGuard {
+		flags += " --base-role-upgrade-guard"
+	}
+	if opts.allowPrivilegeIncrease {
+		flags += " --allow-privilege-increase"
+	}
+	return flags
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+type rateLimit struct {
+	Limit     int   `json:"limit"`
+	Remaining int   `json:"remaining"`
+	Reset     int64 `json:"reset"`
+}
+
+type rateLimitResponse struct {
+	Resources struct {
+		Core    rateLimit `json:"core"`
+		GraphQL rateLimit `json:"graphql"`
+	} `json:"resources"`
+}
+
+func fetchRateLimits(hostname string) (rateLimitResponse, error) {
+	var limits rateLimitResponse
+	response, stderr, err := ghAPI(hostname, "rate_limit")
+	if err != nil {
+		return limits, fmt.Errorf("rate limit lookup failed: %w (%s)", err, stderr.String())
+	}
+	if err := json.Unmarshal(response.Bytes(), &limits); err != nil {
+		return limits, err
+	}
+	return limits, nil
+}
+
+// checkRateLimitBudget displays the remaining REST and GraphQL budget and warns
+// when the estimated number of REST requests for the run exceeds it
+func checkRateLimitBudget(hostname string, estimatedRequests int) {
+	limits, err := fetchRateLimits(hostname)
+	if err != nil {
+		// GHES returns 404 when rate limiting is disabled on the instance
+		if isNotFoundError(err) {
+			pterm.Info.Println("API Rate Limit: not enforced on this host")
+			return
+		}
+		pterm.Warning.Printfln("Unable to check API rate limits: %v", err)
+		return
+	}
+
+	core := limits.Resources.Core
+	graphql := limits.Resources.GraphQL
+	pterm.Info.Printfln("REST API Budget: %d of %d remaining (resets %s)", core.Remaining, core.Limit, formatReset(core.Reset))
+	pterm.Info.Printfln("GraphQL API Budget: %d of %d remaining (resets %s)", graphql.Remaining, graphql.Limit, formatReset(graphql.Reset))
+	pterm.Info.Printfln("Estimated REST Requests: %d", estimatedRequests)
+
+	if estimatedRequests > core.Remaining {
+		pterm.Warning.Printfln("This run is likely to exhaust the REST API budget before it finishes. Consider --delay or splitting the target list.")
+	}
+}
+
+func formatReset(reset int64) string {
+	if reset == 0 {
+		return "unknown"
+	}
+	return "at " + time.Unix(reset, 0).Local().Format("15:04:05")
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2"
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/pterm/pterm"
+)
+
+// reauthState tracks tokens obtained by re-authenticating during a run. The
+// mutex is held while the user re-authenticates, which pauses every worker
+// that hits the same failure until the new token is available.
+var reauthState = struct {
+	sync.Mutex
+	tokens   map[string]string
+	declined map[string]bool
+}{
+	tokens:   map[string]string{},
+	declined: map[string]bool{},
+}
+
+// reauthTransport retries requests that fail with 401 Unauthorized after the
+// user re-authenticates, so an expired SAML session or revoked token part-way
+// through a run does not fail every remaining organization
+type reauthTransport struct {
+	base http.RoundTripper
+}
+
+func (t reauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := normalizeHostname(req.URL.Hostname())
+	if token := refreshedToken(host); token != "" {
+		req = withToken(req, token)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !canRetry(req) {
+		return resp, err
+	}
+
+	token, ok := reauthenticate(host, req.Header.Get("Authorization"))
+	if !ok {
+		return resp, nil
+	}
+	retry := withToken(req, token)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	resp.Body.Close()
+	apiUsage.recordRetry()
+	return t.base.RoundTrip(retry)
+}
+
+func refreshedToken(host string) string {
+	reauthState.Lock()
+	defer reauthState.Unlock()
+	return reauthState.tokens[host]
+}
+
+// canRetry reports whether a request can be sent again: its body, if any,
+// must be replayable, and there must be someone at a terminal to prompt
+func canRetry(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	return term.IsTerminal(os.Stdin) && term.IsTerminal(os.Stdout)
+}
+
+// reauthenticate pauses the run and asks the user to log in again, returning
+// the new token for host. If another worker already re-authenticated after
+// this request was sent, its token is reused without prompting again.
+func reauthenticate(host, failedAuthorization string) (string, bool) {
+	reauthState.Lock()
+	defer reauthState.Unlock()
+
+	if token := reauthState.tokens[host]; token != "" && failedAuthorization != "token "+token {
+		return token, true
+	}
+	if reauthState.declined[host] {
+		return "", false
+	}
+
+	// Logging in again may switch gh's active account away from the one
+	// selected for this run
+	if accountToken(host) != "" {
+		reauthState.declined[host] = true
+		return "", false
+	}
+
+	// A token from the environment cannot be replaced by logging in again
+	if _, source := auth.TokenForHost(host); strings.HasSuffix(source, "_TOKEN") {
+		reauthState.declined[host] = true
+		return "", false
+	}
+
+	pterm.Println()
+	pterm.Warning.Printfln("Authentication to %s failed (HTTP 401). The token may have expired or a SAML session may have ended.", host)
+	login, err := promptConfirm("Re-authenticate with gh auth login and resume the run?")
+	if err != nil || !login {
+		reauthState.declined[host] = true
+		return "", false
+	}
+	if err := gh.ExecInteractive(context.Background(), "auth", "login", "-h", host); err != nil {
+		pterm.Error.Printfln("gh auth login failed: %v", err)
+		reauthState.declined[host] = true
+		return "", false
+	}
+
+	token, _ := auth.TokenForHost(host)
+	if token == "" || "token "+token == failedAuthorization {
+		reauthState.declined[host] = true
+		return "", false
+	}
+	reauthState.tokens[host] = token
+	// Clients created from now on pick up the new token directly
+	resetClients(host)
+	pterm.Success.Println("Re-authenticated. Resuming the run.")
+	return token, true
+}
+
+// withToken returns a copy of req authenticated with token
+func withToken(req *http.Request, token string) *http.Request {
+	clone := req.Clone(req.Context())
+	clone.Header.Set("Authorization", "token "+token)
+	return clone
+}
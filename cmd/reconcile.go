@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of the reconcile command besides 0 (every organization matches)
+// and 1 (errors)
+const (
+	exitDrift      = 2
+	exitDriftFixed = 3
+)
+
+// Deviation kinds printed by the reconcile command, one line each
+const (
+	deviationMissing = "MISSING"
+	deviationDrift   = "DRIFT"
+	deviationFixed   = "FIXED"
+	deviationSkipped = "SKIPPED"
+	deviationError   = "ERROR"
+)
+
+// Summary categories of reconcile deviations that are not errors
+const (
+	categoryRoleMissing = "Role missing"
+	categoryDrift       = "Drift"
+)
+
+// deviation is one organization whose role does not match the definition
+type deviation struct {
+	Kind string
+	Org  string
+	Role string
+	// Owner is the owner annotation of the definition, so alerts reach
+	// whoever is responsible for the role
+	Owner   string
+	Details string
+}
+
+// String formats a deviation as a single line for logs and alerts, such as
+// "DRIFT org=acme role=Developer Base Role: "write" → "maintain""
+func (d deviation) String() string {
+	if d.Owner != "" {
+		return fmt.Sprintf("%s org=%s role=%s owner=%s %s", d.Kind, d.Org, shellQuote(d.Role), shellQuote(d.Owner), d.Details)
+	}
+	return fmt.Sprintf("%s org=%s role=%s %s", d.Kind, d.Org, shellQuote(d.Role), d.Details)
+}
+
+// result returns the run result a deviation is counted as in the summary and
+// --stream events. Missing and drifted roles are warnings, since the exit
+// code already tells them apart from errors.
+func (d deviation) result() targetResult {
+	result := targetResult{Target: d.Org, Message: d.Details}
+	switch d.Kind {
+	case deviationFixed:
+		result.Status = statusSucceeded
+	case deviationMissing:
+		result.Status, result.Category = statusSkipped, categoryRoleMissing
+	case deviationDrift:
+		result.Status, result.Category = statusSkipped, categoryDrift
+	case deviationSkipped:
+		result.Status, result.Category = statusSkipped, d.Details
+	default:
+		result.Status = statusFailed
+	}
+	return result
+}
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Check organizations against a role definition, optionally fixing drift, with exit codes for scheduled runs",
+	Args:  cobra.NoArgs,
+	RunE:  runReconcile,
+}
+
+func init() {
+	// Reconcile command flags
+	reconcileCmd.Flags().StringVarP(&opts.fromFile, "from-file", "f", "", "Read the role name, description, base role, and permissions from a YAML or JSON file (- for stdin)")
+	reconcileCmd.Flags().StringVarP(&opts.roleName, "role-name", "n", "", "Custom role name")
+	reconcileCmd.Flags().StringVarP(&opts.roleDesc, "role-description", "d", "", "Custom role description")
+	reconcileCmd.Flags().StringVarP(&opts.baseRole, "base-role", "b", "", "Base role (read, triage, write, maintain)")
+	reconcileCmd.Flags().StringVarP(&opts.permissions, "permissions", "p", "", "Comma-separated list of permission names")
+	reconcileCmd.Flags().BoolVar(&opts.reconcileFix, "fix", false, "Create missing roles and update drifted roles to match the definition")
+	reconcileCmd.Flags().BoolVar(&opts.markManaged, "mark-managed", false, "Expect roles to carry the managed marker, adding it to roles created or updated with --fix")
+	reconcileCmd.Flags().StringVar(&opts.confirmEnterprise, "confirm-enterprise", "", "Enterprise slug, required to approve --fix with --all-orgs")
+	reconcileCmd.MarkFlagsMutuallyExclusive("from-file", "role-name")
+	reconcileCmd.MarkFlagsMutuallyExclusive("from-file", "role-description")
+	reconcileCmd.MarkFlagsMutuallyExclusive("from-file", "base-role")
+	reconcileCmd.MarkFlagsMutuallyExclusive("from-file", "permissions")
+}
+
+func runReconcile(_ *cobra.Command, _ []string) error {
+	// Scheduled runs have no one to answer a prompt, so missing values are
+	// errors instead
+	noPrompts = true
+	if opts.reconcileFix && readOnly() {
+		return fmt.Errorf("reconcile --fix is disabled because %s is set; run without --fix to report drift", readOnlyEnv)
+	}
+	if opts.fromFile != "" {
+		definition, err := loadRoleFile(opts.fromFile)
+		if err != nil {
+			return err
+		}
+		applyRoleFile(&opts, definition)
+	}
+	// The definition must be complete
+	opts.roleName = strings.TrimSpace(opts.roleName)
+	opts.roleDesc = strings.TrimSpace(opts.roleDesc)
+	if opts.roleName == "" || opts.baseRole == "" || opts.permissions == "" {
+		return errors.New("reconcile needs a role definition: pass --from-file, or --role-name, --base-role, and --permissions")
+	}
+	if opts.markManaged {
+		opts.roleDesc = markManaged(opts.roleDesc)
+	}
+	if opts.hostname == "" {
+		defaultHostname, _ := ghDefaultHost()
+		opts.hostname = defaultHostname
+	}
+
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+	baseRole, err := resolveBaseRole(opts.baseRole)
+	if err != nil {
+		return err
+	}
+
+	if opts.org == "" && !opts.allOrgs && len(opts.orgsCSVPaths) == 0 && opts.orgsFromCmd == "" {
+		return errors.New("reconcile needs targets: pass --org, --all-orgs, --orgs-csv, or --orgs-from-cmd")
+	}
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, opts.allOrgs); err != nil {
+		return err
+	}
+
+	if opts.allOrgs && opts.enterprise == "" {
+		opts.enterprise = defaultEnterpriseForHost(opts.hostname)
+		if opts.enterprise == "" {
+			return errors.New("reconcile --all-orgs needs --enterprise")
+		}
+	}
+	if err := resolveEnterprise(); err != nil {
+		return err
+	}
+
+	orgs, err := resolveOrganizations(opts)
+	if err != nil {
+		return err
+	}
+	if len(orgs) == 0 {
+		return errors.New("no organizations provided")
+	}
+	if opts.reconcileFix && opts.allOrgs {
+		// The slug cannot be typed again in a scheduled run
+		if opts.confirmEnterprise == "" {
+			return errors.New("reconcile --fix with --all-orgs needs --confirm-enterprise with the enterprise slug")
+		}
+		if err := confirmEnterprise(opts, "fix drift"); err != nil {
+			return err
+		}
+	}
+
+	// Validate concurrency and delay bounds
+	if err := validatePacing(opts); err != nil {
+		return err
+	}
+
+	catalog, err := listFineGrainedPermissions(opts.hostname, orgs[0])
+	if err != nil {
+		return err
+	}
+	permissions, err := resolvePermissions(opts.permissions, catalog)
+	if err != nil {
+		return err
+	}
+
+	results := newRunResults(len(orgs))
+
+	var mu sync.Mutex
+	var deviations []deviation
+	report := func(kind, org, format string, args ...any) {
+		deviation := deviation{Kind: kind, Org: org, Role: opts.roleName, Owner: opts.annotations.Owner, Details: redactSecrets(fmt.Sprintf(format, args...))}
+		results.Record(deviation.result())
+		mu.Lock()
+		defer mu.Unlock()
+		deviations = append(deviations, deviation)
+	}
+
+	processQueue(opts, untilStopped(queueTargets(orgs), results.Stopped()), func(org string) {
+		existing, _, findErr := findRole(opts.hostname, org, opts.roleName)
+		switch {
+		case findErr != nil && isNotFoundError(findErr):
+			report(deviationSkipped, org, "organization not found")
+		case isPlanUnsupportedError(findErr):
+			report(deviationSkipped, org, "custom roles not available on this plan")
+		case findErr != nil:
+			report(deviationError, org, "%v", findErr)
+		case existing == nil && !opts.reconcileFix:
+			report(deviationMissing, org, "role does not exist")
+		case existing == nil:
+			if createErr := createCustomRole(opts.hostname, org, opts.roleName, opts.roleDesc, baseRole, permissions); createErr != nil {
+				report(deviationError, org, "create failed: %v", createErr)
+				return
+			}
+			report(deviationFixed, org, "created")
+		default:
+			changes := roleChanges(*existing, existing.Name, opts.roleDesc, baseRole, permissions)
+			increase := blockedPrivilegeIncrease(*existing, baseRole, permissions)
+			switch {
+			case len(changes) == 0:
+				results.Record(targetResult{Target: org, Status: statusSucceeded, Message: "In sync"})
+			case !opts.reconcileFix:
+				report(deviationDrift, org, "%s", formatRoleChanges(changes))
+			case excludedByManagedOnly(*existing):
+				// Unfixed drift still fails the check
+				report(deviationDrift, org, "%s (not fixed: role not managed by gh-custom-roles)", formatRoleChanges(changes))
+			case increase != "":
+				report(deviationDrift, org, "%s (not fixed: privilege increase blocked: %s)", formatRoleChanges(changes), increase)
+			default:
+				if updateErr := updateCustomRole(opts.hostname, org, existing.ID, changes, permissions); updateErr != nil {
+					report(deviationError, org, "update failed: %v", updateErr)
+					return
+				}
+				results.RecordUpdate(org, *existing, changes, permissions)
+				report(deviationFixed, org, "updated: %s", formatRoleChanges(changes))
+			}
+		}
+	})
+
+	sort.Slice(deviations, func(i, j int) bool {
+		return deviations[i].Org < deviations[j].Org
+	})
+	// Deviations follow the rest of the output, which moves to stderr when
+	// --stream writes events to stdout
+	counts := map[string]int{}
+	for _, deviation := range deviations {
+		counts[deviation.Kind]++
+		pterm.Println(deviation)
+	}
+	results.emitFinished()
+
+	inSync := results.Count(statusSucceeded) - counts[deviationFixed]
+	pterm.Info.Printfln("reconcile %s: %d organizations, %d in sync, %d missing, %d drifted, %d fixed, %d skipped, %d errors",
+		opts.roleName, len(orgs), inSync, counts[deviationMissing], counts[deviationDrift], counts[deviationFixed], counts[deviationSkipped], counts[deviationError])
+
+	// Runs that change roles are saved like the other commands' runs, with
+	// the applied changes under --record-changes
+	if opts.reconcileFix {
+		details := []summaryDetail{
+			{Label: "Role Name", Value: opts.roleName},
+			{Label: "Base Role", Value: baseRole},
+			{Label: "Permissions", Value: strings.Join(permissions, ", ")},
+		}
+		details = append(details, opts.annotations.details()...)
+		details = append(details, summaryDetail{Label: "Target Organizations", Value: fmt.Sprintf("%d", len(orgs))})
+		publishRunSummary(opts, "Custom role reconcile: "+opts.roleName, details, results)
+	}
+
+	// An aborted run takes precedence, then errors, then remaining drift, then
+	// drift that was fixed
+	switch {
+	case results.Aborted():
+		return fmt.Errorf("reconcile aborted (%s) with %d organizations not checked", failureLimitFlag(opts), results.Unprocessed())
+	case counts[deviationError] > 0:
+		return fmt.Errorf("reconcile failed in %d organizations", counts[deviationError])
+	case counts[deviationMissing]+counts[deviationDrift] > 0:
+		return &exitCodeError{code: exitDrift, err: fmt.Errorf("drift found in %d organizations", counts[deviationMissing]+counts[deviationDrift])}
+	case counts[deviationFixed] > 0:
+		return &exitCodeError{code: exitDriftFixed, err: fmt.Errorf("drift fixed in %d organizations", counts[deviationFixed])}
+	}
+	return nil
+}
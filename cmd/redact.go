@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"regexp"
+)
+
+// redactedValue replaces secrets removed from output
+const redactedValue = "[REDACTED]"
+
+// secretPatterns match credentials that must never reach the terminal, step
+// summaries, or issues: GitHub tokens, Authorization header values, token
+// environment variables, and private key files
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})\b`),
+	regexp.MustCompile(`(?i)(authorization:\s*(?:token|bearer|basic)\s+)\S+`),
+	regexp.MustCompile(`(?i)\b((?:GH|GITHUB|GH_ENTERPRISE|GITHUB_ENTERPRISE)_TOKEN=)\S+`),
+	regexp.MustCompile(`(?:~|[A-Za-z]:)?[\w.-]*[/\\][\w./\\-]*\.(?:pem|key)\b`),
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+}
+
+// redactSecrets scrubs credentials from text before it is written to any
+// output
+func redactSecrets(text string) string {
+	for _, pattern := range secretPatterns {
+		if pattern.NumSubexp() > 0 {
+			text = pattern.ReplaceAllString(text, "${1}"+redactedValue)
+			continue
+		}
+		text = pattern.ReplaceAllString(text, redactedValue)
+	}
+	return text
+}
@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// formatHTML is the report format: a single HTML page with its styles and
+// scripts inline, so it opens offline and can be attached or emailed as is
+const formatHTML = "html"
+
+// defaultReportPath is where report writes the page without --out
+const defaultReportPath = "report.html"
+
+// reportRole is one row of the roles table. Drift is set when organizations
+// define the role's name differently and this role is not the most common
+// definition.
+type reportRole struct {
+	Org         string
+	Name        string
+	BaseRole    string
+	Permissions []string
+	Drift       string
+}
+
+// reportDrift is one definition of a role name that organizations define in
+// more than one way
+type reportDrift struct {
+	Name        string
+	BaseRole    string
+	Permissions string
+	Orgs        string
+	// Canonical marks the most common definition of the name
+	Canonical  bool
+	Difference string
+}
+
+// reportData is everything the report page shows
+type reportData struct {
+	Title       string
+	Hostname    string
+	GeneratedAt string
+	Orgs        int
+	Roles       []reportRole
+	Definitions int
+	Drift       []reportDrift
+	DriftNames  int
+	Matrix      grantMatrix
+	Errors      int
+}
+
+var reportCmd = &cobra.Command{
+	Use:     "report",
+	Short:   "Write a standalone HTML report of custom roles, drift, and grants for stakeholders",
+	Example: `  gh custom-roles report --all-orgs --enterprise acme --format html --out report.html`,
+	Args:    cobra.NoArgs,
+	RunE:    runReport,
+}
+
+func init() {
+	// Report command flags
+	reportCmd.Flags().StringVar(&opts.reportFormat, "format", formatHTML, "Output format: html")
+	reportCmd.Flags().StringVar(&opts.outputPath, "out", defaultReportPath, "Write the report to this file")
+}
+
+func runReport(_ *cobra.Command, _ []string) error {
+	if opts.outputFormat != formatHTML {
+		return fmt.Errorf("invalid format %q: expected %s", opts.outputFormat, formatHTML)
+	}
+	if strings.TrimSpace(opts.outputPath) == "" {
+		return errors.New("--out must name the report file")
+	}
+
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	if err := selectTargets(); err != nil {
+		return err
+	}
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, opts.allOrgs); err != nil {
+		return err
+	}
+
+	if err := resolveEnterprise(); err != nil {
+		return err
+	}
+
+	orgs, err := resolveOrganizations(opts)
+	if err != nil {
+		return err
+	}
+	if len(orgs) == 0 {
+		return errors.New("no organizations provided")
+	}
+
+	// Validate concurrency and delay bounds
+	if err := validatePacing(opts); err != nil {
+		return err
+	}
+
+	catalog, err := listFineGrainedPermissions(opts.hostname, orgs[0])
+	if err != nil {
+		return err
+	}
+	inventory, err := collectCustomRoles(opts, orgs)
+	if err != nil {
+		return err
+	}
+	data := buildReport(opts.hostname, inventory, catalog, time.Now())
+
+	if err := writeReport(opts.outputPath, data); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	// Display summary
+	pterm.Println()
+	pterm.DefaultSection.Println("Summary")
+	pterm.Success.Printfln("Wrote the report to %s", opts.outputPath)
+	pterm.Info.Printfln("✓ Roles: %d in %d organizations", len(data.Roles), data.Orgs)
+	if data.DriftNames > 0 {
+		pterm.Warning.Printfln("⚠ Role names with drift: %d", data.DriftNames)
+	}
+	if inventory.ErrorCount > 0 {
+		pterm.Error.Printfln("✗ Errors: %d", inventory.ErrorCount)
+	}
+
+	// Display command for replication
+	printReplicationTip("this report", buildReportReplicationCommand(opts))
+
+	if inventory.ErrorCount > 0 {
+		return fmt.Errorf("completed with %d errors", inventory.ErrorCount)
+	}
+	return nil
+}
+
+// buildReport gathers the report from the roles read across organizations.
+// A role drifts when another organization defines its name differently and
+// more organizations share that other definition.
+func buildReport(hostname string, inventory roleInventory, catalog []fineGrainedPermission, now time.Time) reportData {
+	data := reportData{
+		Title:       "Custom repository roles",
+		Hostname:    hostname,
+		GeneratedAt: now.UTC().Format("2006-01-02 15:04 MST"),
+		Orgs:        len(inventory.Orgs),
+		Definitions: len(groupRoleDefinitions(inventory.Roles)),
+		Matrix:      buildGrantMatrix(inventory.Roles, catalog),
+		Errors:      inventory.ErrorCount,
+	}
+
+	drifted := map[string]string{}
+	for _, collision := range nameCollisions(inventory.Roles) {
+		data.DriftNames++
+		canonical := collision.Definitions[0]
+		for i, definition := range collision.Definitions {
+			drift := reportDrift{
+				Name:        collision.Name,
+				BaseRole:    definition.BaseRole,
+				Permissions: strings.Join(definition.Permissions, ", "),
+				Canonical:   i == 0,
+			}
+			var orgs []string
+			for _, role := range definition.Roles {
+				orgs = append(orgs, role.Org)
+			}
+			sort.Strings(orgs)
+			drift.Orgs = strings.Join(orgs, ", ")
+			if i > 0 {
+				drift.Difference = describeDefinitionDrift(canonical, definition)
+				for _, role := range definition.Roles {
+					drifted[role.Org+"\x00"+role.Role.Name] = drift.Difference
+				}
+			}
+			data.Drift = append(data.Drift, drift)
+		}
+	}
+
+	for _, role := range data.Matrix.Roles {
+		data.Roles = append(data.Roles, reportRole{
+			Org:         role.Org,
+			Name:        role.Role.Name,
+			BaseRole:    role.Role.BaseRole,
+			Permissions: role.Role.Permissions,
+			Drift:       drifted[role.Org+"\x00"+role.Role.Name],
+		})
+	}
+	return data
+}
+
+// describeDefinitionDrift describes how definition differs from canonical,
+// base role first
+func describeDefinitionDrift(canonical, definition *roleDefinition) string {
+	var parts []string
+	if definition.BaseRole != canonical.BaseRole {
+		parts = append(parts, fmt.Sprintf("base role %s instead of %s", definition.BaseRole, canonical.BaseRole))
+	}
+	if difference := describeDifference(canonical, definition); difference != "identical" {
+		parts = append(parts, difference)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// writeReport renders data to path
+func writeReport(path string, data reportData) error {
+	file, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := reportTemplate.Execute(file, data); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func buildReportReplicationCommand(opts options) string {
+	cmd := "gh custom-roles report"
+
+	if opts.hostname != "" {
+		cmd += " --hostname " + shellQuote(opts.hostname)
+	}
+	cmd += targetFlags(opts)
+	if opts.outputPath != defaultReportPath {
+		cmd += " --out " + shellQuote(opts.outputPath)
+	}
+	cmd += pacingFlags(opts)
+
+	return cmd
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"join":    strings.Join,
+	"granted": func(role orgRole, permissions []string) []string { return grantCells(role.Role, permissions, "✓") },
+}).Parse(reportPage))
+
+// reportPage is the report template. Clicking a column header sorts its
+// table; the script only runs in the page and makes no requests.
+const reportPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} · {{.Hostname}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #1f2328; }
+h1 { margin-bottom: 0.25rem; }
+.meta { color: #59636e; margin-top: 0; }
+.cards { display: flex; gap: 1rem; flex-wrap: wrap; margin: 1.5rem 0; }
+.card { border: 1px solid #d1d9e0; border-radius: 6px; padding: 0.75rem 1rem; min-width: 9rem; }
+.card strong { display: block; font-size: 1.5rem; }
+.card.warn { border-color: #d4a72c; background: #fff8c5; }
+.card.error { border-color: #cf222e; background: #ffebe9; }
+table { border-collapse: collapse; margin: 1rem 0 2rem; font-size: 0.9rem; }
+th, td { border: 1px solid #d1d9e0; padding: 0.35rem 0.6rem; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; cursor: pointer; user-select: none; white-space: nowrap; }
+th[aria-sort="ascending"]::after { content: " ▲"; }
+th[aria-sort="descending"]::after { content: " ▼"; }
+tr.drift td { background: #fff8c5; }
+tr.canonical td { background: #dafbe1; }
+.matrix td { text-align: center; }
+.matrix td:nth-child(-n+3) { text-align: left; }
+.matrix th.unavailable { color: #cf222e; }
+.scroll { overflow-x: auto; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">{{.Hostname}} · generated {{.GeneratedAt}} by gh-custom-roles</p>
+
+<div class="cards">
+<div class="card"><strong>{{.Orgs}}</strong>organizations</div>
+<div class="card"><strong>{{len .Roles}}</strong>custom roles</div>
+<div class="card"><strong>{{.Definitions}}</strong>distinct definitions</div>
+<div class="card{{if .DriftNames}} warn{{end}}"><strong>{{.DriftNames}}</strong>role names with drift</div>
+{{if .Errors}}<div class="card error"><strong>{{.Errors}}</strong>unreadable organizations</div>{{end}}
+</div>
+
+<h2>Drift</h2>
+{{if .Drift}}
+<p>These role names are defined differently across organizations. The most common definition is highlighted in green, and the others in yellow with how they differ from it.</p>
+<table class="sortable">
+<thead><tr><th>Role</th><th>Base Role</th><th>Permissions</th><th>Organizations</th><th>Difference</th></tr></thead>
+<tbody>
+{{range .Drift}}<tr class="{{if .Canonical}}canonical{{else}}drift{{end}}"><td>{{.Name}}</td><td>{{.BaseRole}}</td><td>{{.Permissions}}</td><td>{{.Orgs}}</td><td>{{if .Canonical}}most common{{else}}{{.Difference}}{{end}}</td></tr>
+{{end}}</tbody>
+</table>
+{{else}}
+<p>Every role name means the same thing in every organization that defines it.</p>
+{{end}}
+
+<h2>Roles</h2>
+<table class="sortable">
+<thead><tr><th>Organization</th><th>Role</th><th>Base Role</th><th>Permissions</th><th>Drift</th></tr></thead>
+<tbody>
+{{range .Roles}}<tr{{if .Drift}} class="drift"{{end}}><td>{{.Org}}</td><td>{{.Name}}</td><td>{{.BaseRole}}</td><td>{{join .Permissions ", "}}</td><td>{{.Drift}}</td></tr>
+{{end}}</tbody>
+</table>
+
+<h2>Grant matrix</h2>
+<div class="scroll">
+<table class="sortable matrix">
+<thead><tr><th>Organization</th><th>Role</th><th>Base Role</th>{{range .Matrix.Permissions}}<th{{if index $.Matrix.Unavailable .}} class="unavailable" title="Not in the host's permission catalog"{{end}}>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{range .Matrix.Roles}}<tr><td>{{.Org}}</td><td>{{.Role.Name}}</td><td>{{.Role.BaseRole}}</td>{{range granted . $.Matrix.Permissions}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>
+</div>
+
+<script>
+document.querySelectorAll("table.sortable th").forEach(function (header) {
+  header.addEventListener("click", function () {
+    var table = header.closest("table");
+    var body = table.tBodies[0];
+    var column = Array.prototype.indexOf.call(header.parentNode.children, header);
+    var ascending = header.getAttribute("aria-sort") !== "ascending";
+    table.querySelectorAll("th").forEach(function (other) { other.removeAttribute("aria-sort"); });
+    header.setAttribute("aria-sort", ascending ? "ascending" : "descending");
+    var rows = Array.prototype.slice.call(body.rows);
+    rows.sort(function (a, b) {
+      var x = a.cells[column].textContent, y = b.cells[column].textContent;
+      return (ascending ? 1 : -1) * x.localeCompare(y, undefined, { numeric: true, sensitivity: "base" });
+    });
+    rows.forEach(function (row) { body.appendChild(row); });
+  });
+});
+</script>
+</body>
+</html>
+`
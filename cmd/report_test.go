@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildReportHighlightsDrift(t *testing.T) {
+	inventory := roleInventory{
+		Orgs: []string{"acme-data", "acme-mobile", "acme-web"},
+		Roles: []orgRole{
+			{Org: "acme-web", Role: customRole{Name: "Developer", BaseRole: "write", Permissions: []string{"add_label"}}},
+			{Org: "acme-mobile", Role: customRole{Name: "developer", BaseRole: "write", Permissions: []string{"add_label"}}},
+			{Org: "acme-data", Role: customRole{Name: "Developer", BaseRole: "maintain", Permissions: []string{"add_label", "close_issue"}}},
+			{Org: "acme-data", Role: customRole{Name: "Reader", BaseRole: "read"}},
+		},
+	}
+	catalog := []fineGrainedPermission{{Name: "add_label"}, {Name: "close_issue"}}
+	data := buildReport("github.com", inventory, catalog, time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC))
+
+	if data.DriftNames != 1 || len(data.Drift) != 2 {
+		t.Fatalf("drift = %d names, %v; want 1 name with 2 definitions", data.DriftNames, data.Drift)
+	}
+	if !data.Drift[0].Canonical || data.Drift[0].Orgs != "acme-mobile, acme-web" {
+		t.Errorf("canonical definition = %+v, want the write role of acme-mobile and acme-web", data.Drift[0])
+	}
+	want := "base role maintain instead of write; +close_issue"
+	if data.Drift[1].Difference != want {
+		t.Errorf("difference = %q, want %q", data.Drift[1].Difference, want)
+	}
+	for _, role := range data.Roles {
+		drifts := role.Org == "acme-data" && role.Name == "Developer"
+		if (role.Drift != "") != drifts {
+			t.Errorf("role %s in %s: drift = %q", role.Name, role.Org, role.Drift)
+		}
+	}
+}
+
+func TestWriteReportIsSelfContained(t *testing.T) {
+	inventory := roleInventory{
+		Orgs:  []string{"acme-web"},
+		Roles: []orgRole{{Org: "acme-web", Role: customRole{Name: "<script>alert(1)</script>", BaseRole: "write", Permissions: []string{"add_label"}}}},
+	}
+	data := buildReport("github.com", inventory, []fineGrainedPermission{{Name: "add_label"}}, time.Now())
+
+	path := filepath.Join(t.TempDir(), "report.html")
+	if err := writeReport(path, data); err != nil {
+		t.Fatalf("writeReport: %v", err)
+	}
+	page, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(page)
+	if strings.Contains(html, "<script>alert(1)</script>") {
+		t.Error("role name is not escaped")
+	}
+	for _, external := range []string{"<link", "src=", "http://", "https://"} {
+		if strings.Contains(html, external) {
+			t.Errorf("report references an external resource (%s)", external)
+		}
+	}
+}
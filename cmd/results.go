@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+// categoryTargetDisplayLimit is how many affected targets are listed per
+// category in printed and markdown summaries
+const categoryTargetDisplayLimit = 50
+
+// categoryTargetLimit is how many affected targets a run keeps per category
+// for its run record. Category counts are always exact.
+const categoryTargetLimit = 10000
+
+// recentResultLimit is how many individual target results a run keeps for
+// its published summary. Counts are always exact; only the per-target rows
+// are bounded, so memory stays flat for runs across thousands of targets.
+const recentResultLimit = 1000
+
+// quietRunThreshold is the number of targets above which successes are no
+// longer printed one per line. Warnings and errors are always printed.
+const quietRunThreshold = 200
+
+// runResults aggregates the outcome of a run: exact counts per status and a
+// bounded ring of the most recent target results. It is safe for concurrent
+// use by workers and serializes the lines it prints.
+type runResults struct {
+	mu         sync.Mutex
+	counts     map[string]int
+	categories map[string]map[string]*resultCategory
+	recent     []targetResult
+	next       int
+	quiet      bool
+	progress   *progressbarPrinter
+	total      int
+	started    time.Time
+	// updates holds every role update with --record-changes; unlike the
+	// per-target results it is not bounded, so the record stays complete
+	updates []roleUpdate
+
+	// failureLimit is the number of failures allowed before the run is
+	// aborted (negative for no limit); stopped is closed when it is exceeded
+	failureLimit int
+	aborted      bool
+	stopped      chan struct{}
+}
+
+// newRunResults returns an empty result set for a run across total targets
+func newRunResults(total int) *runResults {
+	r := &runResults{
+		counts:     map[string]int{},
+		categories: map[string]map[string]*resultCategory{},
+		quiet:      total > quietRunThreshold,
+		total:      total,
+		started:    time.Now(),
+		stopped:    make(chan struct{}),
+	}
+	// --max-failures is validated before any command runs, and --fail-fast
+	// allows no failures at all. Skips never count towards the limit.
+	r.failureLimit, _ = parseFailureLimit(opts.maxFailures, total)
+	if opts.failFast {
+		r.failureLimit = 0
+	}
+	if r.quiet {
+		pterm.Info.Printfln("%d targets: successes are counted in the summary instead of listed individually", total)
+	}
+	emitEvent(streamEvent{Event: eventStarted, Counts: map[string]int{"targets": total}})
+	return r
+}
+
+// Track advances progress by one for every result recorded from now on
+func (r *runResults) Track(progress *progressbarPrinter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.progress = progress
+}
+
+// Succeeded records a successful target and prints the formatted line
+// unless the run is large enough to be quiet
+func (r *runResults) Succeeded(target, message, format string, args ...any) {
+	r.record(targetResult{Target: target, Status: statusSucceeded, Message: message}, func() {
+		if !r.quiet {
+			pterm.Success.Println(redactSecrets(fmt.Sprintf(format, args...)))
+		}
+	})
+}
+
+// Skipped records a target that was skipped with a warning. The message
+// doubles as the reason the summary groups the warning under.
+func (r *runResults) Skipped(target, message, format string, args ...any) {
+	r.SkippedAs(message, target, message, format, args...)
+}
+
+// SkippedAs records a skipped target under a reason category that is counted
+// separately in the summary
+func (r *runResults) SkippedAs(category, target, message, format string, args ...any) {
+	r.record(targetResult{Target: target, Status: statusSkipped, Category: category, Message: message}, func() {
+		pterm.Warning.Println(redactSecrets(fmt.Sprintf(format, args...)))
+	})
+}
+
+// Failed records a target that failed with an error
+func (r *runResults) Failed(target, message, format string, args ...any) {
+	r.FailedAs("", target, message, format, args...)
+}
+
+// FailedAs records a failed target under a cause category that is counted
+// separately in the summary
+func (r *runResults) FailedAs(category, target, message, format string, args ...any) {
+	r.record(targetResult{Target: target, Status: statusFailed, Category: category, Message: message}, func() {
+		pterm.Error.Println(redactSecrets(fmt.Sprintf(format, args...)))
+	})
+}
+
+// Record records a result without printing a line for it, for commands that
+// print their own output
+func (r *runResults) Record(result targetResult) {
+	r.record(result, nil)
+}
+
+func (r *runResults) record(result targetResult, print func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result.Message = redactSecrets(result.Message)
+	result.Category = redactSecrets(result.Category)
+	if hostColumns() {
+		result.Host = opts.hostname
+	}
+	if result.Status == statusFailed && result.Category == "" {
+		result.Category = classifyFailure(result.Message)
+	}
+	if print != nil {
+		print()
+	}
+	emitEvent(streamEvent{Event: result.Status, Target: result.Target, Category: result.Category, Message: result.Message})
+	r.counts[result.Status]++
+	if result.Category != "" {
+		if r.categories[result.Status] == nil {
+			r.categories[result.Status] = map[string]*resultCategory{}
+		}
+		category := r.categories[result.Status][result.Category]
+		if category == nil {
+			category = &resultCategory{Name: result.Category}
+			r.categories[result.Status][result.Category] = category
+		}
+		category.Count++
+		if len(category.Targets) < categoryTargetLimit {
+			category.Targets = append(category.Targets, result.Target)
+		}
+	}
+	if len(r.recent) < recentResultLimit {
+		r.recent = append(r.recent, result)
+	} else {
+		r.recent[r.next] = result
+	}
+	r.next = (r.next + 1) % recentResultLimit
+	if r.progress != nil {
+		r.progress.Increment()
+	}
+
+	if result.Status == statusFailed && r.failureLimit >= 0 && !r.aborted && r.counts[statusFailed] > r.failureLimit {
+		r.aborted = true
+		close(r.stopped)
+		pterm.Error.Printfln("Aborting after %d failures (%s). Waiting for in-flight requests to finish.", r.counts[statusFailed], failureLimitFlag(opts))
+	}
+}
+
+// Stopped is closed when the run is aborted by --max-failures or --fail-fast.
+// Queues wrapped with untilStopped stop handing out targets at that point.
+func (r *runResults) Stopped() <-chan struct{} {
+	return r.stopped
+}
+
+// Aborted reports whether the run was aborted by --max-failures or --fail-fast
+func (r *runResults) Aborted() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.aborted
+}
+
+// Unprocessed returns how many targets were never attempted because the run
+// was aborted
+func (r *runResults) Unprocessed() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.aborted {
+		return 0
+	}
+	processed := 0
+	for _, count := range r.counts {
+		processed += count
+	}
+	return max(r.total-processed, 0)
+}
+
+// Err returns the error a finished run exits with. Failures always fail the
+// run; with --warnings-as-errors, so do warnings.
+func (r *runResults) Err() error {
+	errorCount := r.Count(statusFailed)
+	if warningCount := r.Count(statusSkipped); opts.warningsAsErrors && warningCount > 0 {
+		return fmt.Errorf("completed with %d errors and %d warnings (--warnings-as-errors)", errorCount, warningCount)
+	}
+	if errorCount > 0 {
+		return fmt.Errorf("completed with %d errors", errorCount)
+	}
+	return nil
+}
+
+// Count returns the number of results recorded with the given status
+func (r *runResults) Count(status string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counts[status]
+}
+
+// resultCategory is the number of results recorded under one category and
+// the targets they were recorded for, up to categoryTargetLimit
+type resultCategory struct {
+	Name    string   `json:"name"`
+	Count   int      `json:"count"`
+	Targets []string `json:"targets,omitempty"`
+}
+
+// Categories returns the categories recorded for a status, largest first
+func (r *runResults) Categories(status string) []resultCategory {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var categories []resultCategory
+	for _, category := range r.categories[status] {
+		sorted := append([]string(nil), category.Targets...)
+		sort.Strings(sorted)
+		categories = append(categories, resultCategory{Name: category.Name, Count: category.Count, Targets: sorted})
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Count != categories[j].Count {
+			return categories[i].Count > categories[j].Count
+		}
+		return categories[i].Name < categories[j].Name
+	})
+	return categories
+}
+
+// Total returns the number of results recorded
+func (r *runResults) Total() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	total := 0
+	for _, count := range r.counts {
+		total += count
+	}
+	return total
+}
+
+// Recent returns the retained results, oldest first
+func (r *runResults) Recent() []targetResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.recent) < recentResultLimit {
+		return append([]targetResult(nil), r.recent...)
+	}
+	return append(append([]targetResult(nil), r.recent[r.next:]...), r.recent[:r.next]...)
+}
+
+// RecordUpdate keeps the exact change applied to a role for the run record
+// when --record-changes is set
+func (r *runResults) RecordUpdate(target string, current customRole, changes []roleChange, permissions []string) {
+	if !opts.recordChanges {
+		return
+	}
+	update := roleUpdate{Target: target, RoleID: current.ID, Role: current.Name, Patch: rolePatch(current, changes, permissions)}
+	if hostColumns() {
+		update.Host = opts.hostname
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.updates = append(r.updates, update)
+}
+
+// Updates returns the recorded role updates, sorted by target
+func (r *runResults) Updates() []roleUpdate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	updates := append([]roleUpdate(nil), r.updates...)
+	sort.SliceStable(updates, func(i, j int) bool { return updates[i].Target < updates[j].Target })
+	return updates
+}
+
+// printSummaryCounts prints the counts section shared by every run summary
+func (r *runResults) printSummaryCounts(successLabel string) {
+	pterm.Info.Printfln("✓ Successfully %s: %d", successLabel, r.Count(statusSucceeded))
+	if warnings := r.Count(statusSkipped); warnings > 0 {
+		pterm.Warning.Printfln("⚠ Warnings: %d", warnings)
+		r.printCategories(statusSkipped)
+	}
+	if errors := r.Count(statusFailed); errors > 0 {
+		pterm.Error.Printfln("✗ Errors: %d", errors)
+		r.printCategories(statusFailed)
+	}
+	if r.Aborted() {
+		pterm.Error.Printfln("✗ Aborted (%s): %d targets not processed", failureLimitFlag(opts), r.Unprocessed())
+	}
+
+	r.emitFinished()
+
+	stats := apiUsage.Snapshot()
+	pterm.Info.Printfln("Duration: %s", r.Elapsed())
+	pterm.Info.Printfln("API requests: %s", stats)
+	pterm.Info.Printfln("Retries: %d · Time rate-limited: %s", stats.Retries, stats.RateLimited())
+}
+
+// emitFinished streams the final counts of the run
+func (r *runResults) emitFinished() {
+	emitEvent(streamEvent{Event: eventFinished, Counts: map[string]int{
+		statusSucceeded: r.Count(statusSucceeded),
+		statusSkipped:   r.Count(statusSkipped),
+		statusFailed:    r.Count(statusFailed),
+		"unprocessed":   r.Unprocessed(),
+	}})
+}
+
+// Elapsed returns the wall-clock time since the run started
+func (r *runResults) Elapsed() time.Duration {
+	return time.Since(r.started).Round(100 * time.Millisecond)
+}
+
+func (r *runResults) printCategories(status string) {
+	printCategories(r.Categories(status))
+}
+
+// printCategories prints each category's count and affected targets
+func printCategories(categories []resultCategory) {
+	for _, category := range categories {
+		pterm.Printfln("    %s: %d", category.Name, category.Count)
+		if len(category.Targets) > 0 {
+			pterm.Printfln("      %s", formatCategoryTargets(category))
+		}
+	}
+}
+
+// formatCategoryTargets lists up to categoryTargetDisplayLimit targets of a
+// category
+func formatCategoryTargets(category resultCategory) string {
+	shown := category.Targets[:min(len(category.Targets), categoryTargetDisplayLimit)]
+	if more := category.Count - len(shown); more > 0 {
+		return fmt.Sprintf("%s, and %d more", strings.Join(shown, ", "), more)
+	}
+	return strings.Join(shown, ", ")
+}
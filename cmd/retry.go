@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRetries is the highest --retries value
+const maxRetries = 10
+
+// secondaryLimitWait is how long a request waits after a secondary rate limit
+// response without a Retry-After header, as GitHub's documentation advises
+const secondaryLimitWait = time.Minute
+
+// retryTransport sends a request again, up to --retries times, when it fails
+// with a transient server error or a secondary rate limit. Writes made with
+// POST are only retried after a rate limit response, since a server error may
+// hide a role that was created anyway.
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+func (t retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	for attempt := 1; attempt <= opts.retries && err == nil; attempt++ {
+		wait, retry := transientFailure(req, resp)
+		if !retry || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+			break
+		}
+		next := req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				break
+			}
+			next.Body = body
+		}
+		resp.Body.Close()
+		if wait == 0 {
+			// 1s, 2s, 4s, and so on, up to a minute
+			wait = min(time.Second<<(attempt-1), time.Minute)
+		}
+		select {
+		case <-time.After(wait + jitterDelay(opts.jitter)):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		apiUsage.recordRetry()
+		resp, err = t.base.RoundTrip(next)
+	}
+	return resp, err
+}
+
+// transientFailure reports whether resp is worth retrying and how long the
+// server asked to wait first, or 0 for the default backoff
+func transientFailure(req *http.Request, resp *http.Response) (time.Duration, bool) {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || (resp.StatusCode == http.StatusForbidden && secondaryRateLimited(resp)):
+		if wait := retryAfter(resp); wait > 0 {
+			return wait, true
+		}
+		return secondaryLimitWait, true
+	case resp.StatusCode >= http.StatusInternalServerError && !createsContent(req):
+		return retryAfter(resp), true
+	}
+	return 0, false
+}
+
+// createsContent reports whether req may have created something before a
+// server error: a REST POST or a GraphQL mutation. GraphQL queries are sent
+// with POST too, but are safe to repeat.
+func createsContent(req *http.Request) bool {
+	if req.Method != http.MethodPost {
+		return false
+	}
+	if !strings.HasSuffix(req.URL.Path, "/graphql") || req.GetBody == nil {
+		return true
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return true
+	}
+	defer body.Close()
+	var payload struct {
+		Query string `json:"query"`
+	}
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return true
+	}
+	query := strings.TrimSpace(payload.Query)
+	return !strings.HasPrefix(query, "query") && !strings.HasPrefix(query, "{")
+}
+
+// secondaryRateLimited reports whether a 403 response is a secondary rate
+// limit rather than missing access. The body is read and put back for the
+// caller.
+func secondaryRateLimited(resp *http.Response) bool {
+	if resp.Header.Get("Retry-After") != "" {
+		return true
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return err == nil && strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
+
+// retryAfter returns the wait in the Retry-After header, or 0 without one
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(resp.Header.Get("Retry-After")))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// jitterDelay returns a random wait of up to jitter seconds, so workers and
+// retries do not hit the API in lockstep
+func jitterDelay(jitter float64) time.Duration {
+	if jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Float64() * jitter * float64(time.Second))
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// roleFile is a single custom role described in a YAML or JSON file,
+// using the same field names as the REST API
+type roleFile struct {
+	Name        string   `yaml:"name" json:"name"`
+	Description string   `yaml:"description" json:"description"`
+	BaseRole    string   `yaml:"base_role" json:"base_role"`
+	Permissions []string `yaml:"permissions" json:"permissions"`
+	// Annotations are never sent to the API
+	Annotations roleAnnotations `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+}
+
+// roleAnnotations is governance metadata kept with a role definition, such as
+// who owns the role and why it exists. The API has nowhere to store it, so it
+// is carried into run summaries, run history, and reconcile reports instead.
+type roleAnnotations struct {
+	Owner         string `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Justification string `yaml:"justification,omitempty" json:"justification,omitempty"`
+	ReviewDate    string `yaml:"review_date,omitempty" json:"review_date,omitempty"`
+	// Expires is when a time-boxed role must be recertified or removed
+	Expires string `yaml:"expires,omitempty" json:"expires,omitempty"`
+}
+
+// details returns the annotations that are set, labeled for run summaries
+func (a roleAnnotations) details() []summaryDetail {
+	var details []summaryDetail
+	for _, detail := range []summaryDetail{
+		{Label: "Owner", Value: a.Owner},
+		{Label: "Justification", Value: a.Justification},
+		{Label: "Review Date", Value: a.ReviewDate},
+		{Label: "Expires", Value: a.Expires},
+	} {
+		if detail.Value != "" {
+			details = append(details, detail)
+		}
+	}
+	return details
+}
+
+// loadRoleFile reads a role definition file, or stdin when path is "-".
+// JSON is valid YAML, so one decoder handles both formats. Unknown fields are
+// rejected so a typo does not silently drop part of a reviewed definition.
+func loadRoleFile(path string) (roleFile, error) {
+	var definition roleFile
+
+	var input io.Reader = os.Stdin
+	if path == "-" {
+		path = "from stdin"
+	} else {
+		file, err := os.Open(filepath.Clean(path))
+		if err != nil {
+			return definition, fmt.Errorf("failed to open role definition: %w", err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	decoder := yaml.NewDecoder(input)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&definition); err != nil {
+		return definition, fmt.Errorf("failed to parse role definition %s: %w", path, err)
+	}
+
+	definition.Name = strings.TrimSpace(definition.Name)
+	if definition.Name == "" {
+		return definition, fmt.Errorf("role definition %s has no name", path)
+	}
+	if definition.BaseRole == "" {
+		return definition, fmt.Errorf("role definition %s has no base_role", path)
+	}
+	if len(definition.Permissions) == 0 {
+		return definition, fmt.Errorf("role definition %s has no permissions", path)
+	}
+	annotations := &definition.Annotations
+	annotations.Owner = strings.TrimSpace(annotations.Owner)
+	annotations.Justification = strings.TrimSpace(annotations.Justification)
+	annotations.ReviewDate = strings.TrimSpace(annotations.ReviewDate)
+	annotations.Expires = strings.TrimSpace(annotations.Expires)
+	for field, value := range map[string]string{"review_date": annotations.ReviewDate, "expires": annotations.Expires} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return definition, fmt.Errorf("role definition %s has an invalid %s %q: expected YYYY-MM-DD", path, field, value)
+		}
+	}
+	return definition, nil
+}
+
+// applyRoleFile fills the role flags from a definition file
+func applyRoleFile(opts *options, definition roleFile) {
+	opts.roleName = definition.Name
+	opts.roleDesc = definition.Description
+	opts.baseRole = definition.BaseRole
+	opts.permissions = strings.Join(definition.Permissions, ",")
+	opts.annotations = definition.Annotations
+}
@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
@@ -9,32 +12,159 @@ import (
 
 var rootCmd = &cobra.Command{
 	Use:   "custom-roles",
-	Short: "Create custom repository roles in GitHub organizations",
+	Short: "Manage custom repository roles in GitHub organizations",
 	CompletionOptions: cobra.CompletionOptions{
 		HiddenDefaultCmd: true,
 	},
+	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+		// Accessible mode prints plain text without colors or live-updating output
+		if accessible {
+			pterm.DisableStyling()
+		}
+		if err := checkReadOnly(cmd); err != nil {
+			return err
+		}
+		setHostnames()
+		if opts.reportIssue != "" && !issueReferencePattern.MatchString(strings.TrimSpace(opts.reportIssue)) {
+			return fmt.Errorf("invalid --report-issue %q: expected owner/repo or owner/repo#number", opts.reportIssue)
+		}
+		if opts.shard != "" {
+			if _, _, err := parseShard(opts.shard); err != nil {
+				return err
+			}
+		}
+		if _, err := parseOrgFilter(opts); err != nil {
+			return err
+		}
+		if _, err := parseFailureLimit(opts.maxFailures, 0); err != nil {
+			return err
+		}
+		if err := validatePaceProfile(cmd); err != nil {
+			return err
+		}
+		if opts.requestRate < 0 {
+			return fmt.Errorf("requests per second must be non-negative (got %g)", opts.requestRate)
+		}
+		if opts.retries < 0 || opts.retries > maxRetries {
+			return fmt.Errorf("retries must be between 0 and %d (got %d)", maxRetries, opts.retries)
+		}
+		if opts.jitter < 0 {
+			return fmt.Errorf("jitter must be non-negative (got %g)", opts.jitter)
+		}
+		// Each command binds --format to its own field with its own default
+		if format := cmd.Flags().Lookup("format"); format != nil {
+			opts.outputFormat = format.Value.String()
+		}
+		if opts.stream != "" {
+			if opts.stream == "-" && opts.outputPath == "" && opts.outputFormat != "" && opts.outputFormat != formatTable {
+				return fmt.Errorf("--stream and --format %s cannot both write to stdout; pass --stream=FILE or --output", opts.outputFormat)
+			}
+			if err := openEventStream(opts.stream, cmd.Name()); err != nil {
+				return fmt.Errorf("failed to open --stream output: %w", err)
+			}
+		}
+		return nil
+	},
 }
 
 func init() {
 	// Root command flags (persistent for all subcommands)
-	rootCmd.PersistentFlags().StringVarP(&opts.hostname, "hostname", "u", "", "GitHub hostname")
+	rootCmd.PersistentFlags().StringArrayVarP(&opts.hostnames, "hostname", "u", nil, "GitHub hostname (repeatable to run against several hosts at the same time)")
+	rootCmd.PersistentFlags().StringVar(&opts.user, "user", "", "gh account to run as when several accounts are logged in to the host")
 	rootCmd.PersistentFlags().StringVarP(&opts.enterprise, "enterprise", "e", "", "GitHub enterprise slug")
 	rootCmd.PersistentFlags().StringVarP(&opts.org, "org", "o", "", "Target a single organization")
 	rootCmd.PersistentFlags().BoolVarP(&opts.allOrgs, "all-orgs", "a", false, "Target all organizations in the enterprise")
-	rootCmd.PersistentFlags().StringVarP(&opts.orgsCSVPath, "orgs-csv", "c", "", "CSV file path with organizations to target")
+	rootCmd.PersistentFlags().StringArrayVarP(&opts.orgsCSVPaths, "orgs-csv", "c", nil, "CSV file, or directory of CSV files, with organizations to target (repeatable)")
+	rootCmd.PersistentFlags().StringVar(&opts.orgsFromCmd, "orgs-from-cmd", "", "Run this shell command and target the organizations it prints, one per line")
+	rootCmd.PersistentFlags().StringVar(&opts.orgsCSVColumn, "orgs-csv-column", "", "Read organizations from this named column of the --orgs-csv file (first row is the header)")
+	rootCmd.PersistentFlags().StringVar(&opts.shard, "shard", "", "Process only slice INDEX of COUNT of the target organizations, as INDEX/COUNT (for example 2/5)")
+	rootCmd.PersistentFlags().StringVar(&opts.orgsCreatedAfter, "orgs-created-after", "", "With --all-orgs, only target organizations created on or after this date (YYYY-MM-DD)")
+	rootCmd.PersistentFlags().BoolVar(&opts.managedOnly, "managed-only", false, "Only read and change roles marked as managed by gh-custom-roles (see create --mark-managed)")
+	rootCmd.PersistentFlags().BoolVar(&opts.upgradeGuard, "base-role-upgrade-guard", false, "Block updates to existing roles that raise their base role or add sensitive permissions")
+	rootCmd.PersistentFlags().BoolVar(&opts.allowPrivilegeIncrease, "allow-privilege-increase", false, "Apply privilege increases that --base-role-upgrade-guard would block")
+	rootCmd.PersistentFlags().IntVar(&opts.minRepos, "min-repos", 0, "With --all-orgs, only target organizations with at least this many repositories")
 	rootCmd.PersistentFlags().IntVarP(&opts.concurrency, "concurrency", "x", 1, "Number of parallel requests (1-20, mutually exclusive with --delay)")
 	rootCmd.PersistentFlags().IntVarP(&opts.delay, "delay", "w", 0, "Seconds to wait between role creations (mutually exclusive with --concurrency)")
-	rootCmd.MarkFlagsMutuallyExclusive("org", "all-orgs", "orgs-csv")
+	rootCmd.PersistentFlags().Float64Var(&opts.requestRate, "requests-per-second", defaultRequestRate, "Maximum API requests per second to a host across all workers (0 disables)")
+	rootCmd.PersistentFlags().IntVar(&opts.retries, "retries", 0, "Retry requests that fail with a server error or secondary rate limit up to this many times (0-10)")
+	rootCmd.PersistentFlags().Float64Var(&opts.jitter, "jitter", 0, "Wait a random time of up to this many seconds before each target and retry")
+	rootCmd.PersistentFlags().StringVar(&opts.maxFailures, "max-failures", "", "Abort the run once failures exceed this count or percentage of targets (for example 10 or 5%)")
+	rootCmd.PersistentFlags().StringVar(&opts.paceProfile, "profile-pace", "", "Pacing profile tuned for the host type: gentle, normal, or aggressive (explicit pacing flags take precedence)")
+	rootCmd.PersistentFlags().BoolVar(&opts.failFast, "fail-fast", false, "Abort the run at the first failure; skipped targets do not count as failures")
+	rootCmd.PersistentFlags().BoolVar(&opts.warningsAsErrors, "warnings-as-errors", false, "Exit with an error when any target was skipped with a warning, such as an existing role or a missing organization")
+	rootCmd.PersistentFlags().StringVar(&opts.reportIssue, "report-issue", "", "Report the run summary to a new issue in owner/repo, or as a comment on owner/repo#number")
+	rootCmd.PersistentFlags().StringVar(&opts.stream, "stream", "", "Write one JSON event per target state change to stdout, or to a file with --stream=FILE")
+	rootCmd.PersistentFlags().Lookup("stream").NoOptDefVal = "-"
+	rootCmd.PersistentFlags().BoolVar(&opts.recordChanges, "record-changes", false, "Save the field-level changes applied to each updated role in the run record, as JSON Patch (RFC 6902) operations")
+	rootCmd.PersistentFlags().BoolVar(&opts.reportGist, "report-gist", false, "Upload the run summary (markdown and JSON) as a secret gist and print its URL")
+	rootCmd.PersistentFlags().BoolVar(&opts.refreshPermissions, "refresh-permissions", false, "Fetch the fine-grained permission catalog from the host instead of the cached copy")
+	rootCmd.PersistentFlags().BoolVar(&noTip, "no-tip", false, "Do not print the replication command tip (always hidden when output is not a terminal)")
+	rootCmd.PersistentFlags().BoolVar(&accessible, "accessible", false, "Use plain sequential output and numbered prompts (screen-reader friendly)")
+	_ = rootCmd.RegisterFlagCompletionFunc("hostname", completeHostnames)
+	_ = rootCmd.RegisterFlagCompletionFunc("user", completeAccounts)
+	_ = rootCmd.RegisterFlagCompletionFunc("profile-pace", cobra.FixedCompletions(paceProfileNames, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.MarkFlagsMutuallyExclusive("org", "all-orgs", "orgs-csv", "orgs-from-cmd")
 	rootCmd.MarkFlagsMutuallyExclusive("delay", "concurrency")
+	rootCmd.MarkFlagsMutuallyExclusive("max-failures", "fail-fast")
 
-	// Register create command
+	// Register subcommands
 	rootCmd.AddCommand(createCmd)
+	rootCmd.AddCommand(editCmd)
+	rootCmd.AddCommand(viewCmd)
+	rootCmd.AddCommand(deleteCmd)
+	rootCmd.AddCommand(assignCmd)
+	rootCmd.AddCommand(migrateGrantsCmd)
+	rootCmd.AddCommand(copyPermissionsCmd)
+	rootCmd.AddCommand(normalizeCmd)
+	rootCmd.AddCommand(reconcileCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(whoCanCmd)
+	rootCmd.AddCommand(permissionsCmd)
+	rootCmd.AddCommand(simulateCmd)
+	rootCmd.AddCommand(analyzeCmd)
+	rootCmd.AddCommand(grantMatrixCmd)
+	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(assignmentsCmd)
+	rootCmd.AddCommand(aliasCmd)
+	rootCmd.AddCommand(runsCmd)
+	rootCmd.AddCommand(orgsCmd)
+	rootCmd.AddCommand(apiCompatCmd)
 }
 
 // Execute initializes and runs the command.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		pterm.Error.Printfln("Error: %v", err)
+	args, err := expandAlias(os.Args[1:])
+	if err != nil {
+		pterm.Error.Printfln("Error: %s", err)
 		os.Exit(1)
 	}
+	rootCmd.SetArgs(args)
+	forEachHost(rootCmd)
+
+	err = rootCmd.Execute()
+	closeEventStream()
+	if err != nil {
+		pterm.Error.Printfln("Error: %s", redactSecrets(err.Error()))
+		reportHostResult(err)
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
+		os.Exit(1)
+	}
+}
+
+// exitCodeError is an error that exits the process with a specific code
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
 }
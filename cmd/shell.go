@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// posixSafePattern matches values that need no quoting in a POSIX shell
+var posixSafePattern = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// powerShellSafePattern matches values that need no quoting in PowerShell.
+// Commas build arrays and a leading @ splats a variable there, so both are
+// quoted.
+var powerShellSafePattern = regexp.MustCompile(`^[A-Za-z0-9_%+=:./-]+$`)
+
+// powerShellQuotes are the characters PowerShell accepts as a single quote,
+// including the typographic quotes that editors and chat clients substitute
+var powerShellQuotes = strings.NewReplacer("'", "''", "‘", "‘‘", "’", "’’", "‚", "‚‚", "‛", "‛‛")
+
+// shellQuote quotes a value for the shell the replication command will be
+// pasted into: PowerShell on Windows, a POSIX shell everywhere else. Single
+// quotes keep spaces, $, backticks and globs literal in both.
+func shellQuote(value string) string {
+	return quoteForShell(value, runtime.GOOS == "windows")
+}
+
+// quoteForShell quotes a value for PowerShell or for a POSIX shell
+func quoteForShell(value string, powerShell bool) string {
+	if powerShell {
+		if powerShellSafePattern.MatchString(value) {
+			return value
+		}
+		// PowerShell escapes a single quote inside single quotes by doubling it
+		return "'" + powerShellQuotes.Replace(value) + "'"
+	}
+	if posixSafePattern.MatchString(value) {
+		return value
+	}
+	// POSIX shells cannot escape inside single quotes, so close the quote,
+	// add an escaped quote and reopen it
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
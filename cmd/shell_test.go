@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestQuoteForShell(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		posix      string
+		powerShell string
+	}{
+		{name: "plain", value: "acme-web", posix: "acme-web", powerShell: "acme-web"},
+		{name: "path", value: "orgs/prod.csv", posix: "orgs/prod.csv", powerShell: "orgs/prod.csv"},
+		{name: "space", value: "Security Reviewer", posix: "'Security Reviewer'", powerShell: "'Security Reviewer'"},
+		{name: "single quote", value: "Owner's role", posix: `'Owner'\''s role'`, powerShell: "'Owner''s role'"},
+		{name: "typographic quote", value: "Owner’s role", posix: "'Owner’s role'", powerShell: "'Owner’’s role'"},
+		{name: "double quote", value: `say "hi"`, posix: `'say "hi"'`, powerShell: `'say "hi"'`},
+		{name: "dollar", value: "$HOME", posix: "'$HOME'", powerShell: "'$HOME'"},
+		{name: "subexpression", value: "$(whoami)", posix: "'$(whoami)'", powerShell: "'$(whoami)'"},
+		{name: "backtick", value: "a`b", posix: "'a`b'", powerShell: "'a`b'"},
+		{name: "newline", value: "line one\nline two", posix: "'line one\nline two'", powerShell: "'line one\nline two'"},
+		{name: "comma", value: "write_only,read_only", posix: "write_only,read_only", powerShell: "'write_only,read_only'"},
+		{name: "leading at", value: "@acme/platform", posix: "@acme/platform", powerShell: "'@acme/platform'"},
+		{name: "inner at", value: "dev@acme.com", posix: "dev@acme.com", powerShell: "'dev@acme.com'"},
+		{name: "empty", value: "", posix: "''", powerShell: "''"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quoteForShell(tt.value, false); got != tt.posix {
+				t.Errorf("POSIX: quoteForShell(%q) = %s, want %s", tt.value, got, tt.posix)
+			}
+			if got := quoteForShell(tt.value, true); got != tt.powerShell {
+				t.Errorf("PowerShell: quoteForShell(%q) = %s, want %s", tt.value, got, tt.powerShell)
+			}
+		})
+	}
+}
+
+// TestQuoteForShellPOSIXRoundTrip checks that sh reads every quoted value
+// back unchanged
+func TestQuoteForShellPOSIXRoundTrip(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+	values := []string{"Owner's role", `say "hi"`, "$HOME", "$(whoami)", "a`b", "line one\nline two", "*.csv", "a;b|c&d", "@acme,platform", "~root", "back\\slash"}
+	for _, value := range values {
+		out, err := exec.Command(sh, "-c", "printf %s "+quoteForShell(value, false)).Output()
+		if err != nil {
+			t.Fatalf("sh failed for %q: %v", value, err)
+		}
+		if string(out) != value {
+			t.Errorf("sh read %q back as %q", value, out)
+		}
+	}
+}
@@ -0,0 +1,375 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// accessGrant is one way a user or team gets a role on a repository
+type accessGrant struct {
+	Source string
+	Role   string
+}
+
+// effectiveAccess is the access that results from a set of grants: the
+// highest base role and every fine-grained permission added by custom roles
+type effectiveAccess struct {
+	BaseRole    string
+	Sources     []string
+	Permissions []string
+}
+
+// accessRank orders every repository role a grant can resolve to, including
+// admin and no access
+func accessRank(role string) int {
+	if role == "admin" {
+		return len(baseRoleRank) + 1
+	}
+	return baseRoleRank[role]
+}
+
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Compute the effective access a user or team has on a repository",
+	Args:  cobra.NoArgs,
+	RunE:  runSimulate,
+}
+
+func init() {
+	// Simulate command flags. --user names the subject here, so the gh
+	// account is chosen with the account picker instead.
+	simulateCmd.Flags().StringVar(&opts.repo, "repo", "", "Repository to simulate access on, as repo or org/repo")
+	simulateCmd.Flags().StringVar(&opts.subjectUser, "user", "", "User whose access to compute")
+	simulateCmd.Flags().StringVarP(&opts.team, "team", "t", "", "Team slug whose access to compute")
+	simulateCmd.MarkFlagsMutuallyExclusive("user", "team")
+}
+
+func runSimulate(_ *cobra.Command, _ []string) error {
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	if opts.allOrgs || len(opts.orgsCSVPaths) > 0 || opts.orgsFromCmd != "" {
+		return errors.New("simulate computes access on a single repository; use --org and --repo")
+	}
+	if opts.repo == "" {
+		opts.repo, err = promptText("Repository (repo or org/repo)")
+		if err != nil {
+			return err
+		}
+	}
+	repo := strings.TrimSpace(opts.repo)
+	if org, name, ok := strings.Cut(repo, "/"); ok {
+		if opts.org != "" && normalizeOrg(opts.org) != normalizeOrg(org) {
+			return fmt.Errorf("--repo %s is not in --org %s", repo, opts.org)
+		}
+		opts.org, repo = org, name
+	}
+	if opts.org == "" {
+		opts.org, err = promptText("Organization name")
+		if err != nil {
+			return err
+		}
+	}
+	org := normalizeOrg(opts.org)
+	if org == "" || repo == "" {
+		return errors.New("organization and repository are required")
+	}
+
+	if opts.subjectUser == "" && opts.team == "" {
+		subject, err := promptSelect("Compute access for", []string{"User", "Team"}, "User")
+		if err != nil {
+			return err
+		}
+		if subject == "User" {
+			opts.subjectUser, err = promptText("User login")
+		} else {
+			opts.team, err = promptText("Team slug")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	opts.subjectUser = strings.TrimPrefix(strings.TrimSpace(opts.subjectUser), "@")
+	opts.team = strings.ToLower(strings.TrimSpace(opts.team))
+	if opts.subjectUser == "" && opts.team == "" {
+		return errors.New("a user or team is required")
+	}
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, false); err != nil {
+		return err
+	}
+
+	spinner, err := startSpinner("Resolving grants...")
+	if err != nil {
+		return err
+	}
+	var grants []accessGrant
+	var reported string
+	if opts.subjectUser != "" {
+		grants, reported, err = userGrants(opts.hostname, org, repo, opts.subjectUser)
+	} else {
+		grants, err = teamGrants(opts.hostname, org, repo, opts.team)
+	}
+	if err != nil {
+		spinner.Fail("Failed to resolve grants")
+		return err
+	}
+	roles, err := listCustomRoles(opts.hostname, org)
+	if err != nil {
+		spinner.Fail("Failed to read custom roles")
+		return err
+	}
+	spinner.Success(fmt.Sprintf("Resolved %s", pluralize(len(grants), "grant", "grants")))
+
+	access := resolveEffectiveAccess(grants, roles)
+
+	subject := "user " + opts.subjectUser
+	if opts.team != "" {
+		subject = "team " + opts.team
+	}
+
+	pterm.Println()
+	pterm.DefaultSection.Printfln("Grants for %s on %s/%s", subject, org, repo)
+	if len(grants) == 0 {
+		pterm.Info.Println("No grant gives this subject access to the repository.")
+	} else {
+		customRoles := map[string]customRole{}
+		for _, role := range roles {
+			customRoles[roleNameKey(role.Name)] = role
+		}
+		data := pterm.TableData{{"Source", "Role", "Kind", "Base Role", "Additional Permissions"}}
+		for _, grant := range grants {
+			kind, base, permissions := roleKind(grant.Role), grant.Role, ""
+			if role, ok := customRoles[roleNameKey(grant.Role)]; ok && kind == roleKindCustom {
+				base, permissions = role.BaseRole, strings.Join(role.Permissions, ", ")
+			}
+			data = append(data, []string{grant.Source, grant.Role, kind, base, permissions})
+		}
+		if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+			return err
+		}
+	}
+
+	pterm.Println()
+	pterm.DefaultSection.Println("Effective access")
+	if access.BaseRole == "" {
+		pterm.Info.Println("Base Role: none")
+	} else {
+		pterm.Info.Printfln("Base Role: %s (from %s)", access.BaseRole, strings.Join(access.Sources, ", "))
+	}
+	switch {
+	case access.BaseRole == "admin":
+		pterm.Info.Println("Additional Permissions: none needed, admin includes every permission")
+	case len(access.Permissions) == 0:
+		pterm.Info.Println("Additional Permissions: none")
+	default:
+		pterm.Info.Printfln("Additional Permissions: %d", len(access.Permissions))
+		for _, permission := range access.Permissions {
+			pterm.Printfln("  %s", permission)
+		}
+	}
+	if reported != "" {
+		if reported == access.BaseRole || roleKind(reported) == roleKindCustom {
+			pterm.Info.Printfln("GitHub reports the user's role as %s", reported)
+		} else {
+			pterm.Warning.Printfln("GitHub reports the user's role as %s; grants this command cannot see (such as enterprise ownership) may apply", reported)
+		}
+	}
+
+	// Display command for replication
+	printReplicationTip("this simulation", buildSimulateReplicationCommand(opts, org, repo))
+
+	return nil
+}
+
+// userGrants returns every grant that gives a user access to a repository:
+// organization ownership, the organization's base permission, repository
+// visibility, teams the user belongs to, and a direct collaborator role. It
+// also returns the role GitHub reports for the user, when visible.
+func userGrants(hostname, org, repo, login string) ([]accessGrant, string, error) {
+	var grants []accessGrant
+
+	var membership struct {
+		State string `json:"state"`
+		Role  string `json:"role"`
+	}
+	member := false
+	response, stderr, err := ghAPI(hostname, "orgs/"+org+"/memberships/"+login)
+	switch {
+	case err != nil && !isNotFoundError(err):
+		return nil, "", fmt.Errorf("membership lookup failed: %w (%s)", err, stderr.String())
+	case err == nil:
+		if err := json.Unmarshal(response.Bytes(), &membership); err != nil {
+			return nil, "", err
+		}
+		member = membership.State == "active"
+	}
+	if member && membership.Role == "admin" {
+		grants = append(grants, accessGrant{Source: "organization owner", Role: "admin"})
+	}
+
+	var settings struct {
+		DefaultRepositoryPermission string `json:"default_repository_permission"`
+	}
+	response, stderr, err = ghAPI(hostname, "orgs/"+org)
+	if err != nil {
+		return nil, "", fmt.Errorf("organization lookup failed: %w (%s)", err, stderr.String())
+	}
+	if err := json.Unmarshal(response.Bytes(), &settings); err != nil {
+		return nil, "", err
+	}
+	if member && settings.DefaultRepositoryPermission != "" && settings.DefaultRepositoryPermission != "none" {
+		grants = append(grants, accessGrant{Source: "organization base permission", Role: settings.DefaultRepositoryPermission})
+	}
+
+	var repository struct {
+		Visibility string `json:"visibility"`
+	}
+	response, stderr, err = ghAPI(hostname, "repos/"+org+"/"+repo)
+	if err != nil {
+		return nil, "", fmt.Errorf("repository lookup failed: %w (%s)", err, stderr.String())
+	}
+	if err := json.Unmarshal(response.Bytes(), &repository); err != nil {
+		return nil, "", err
+	}
+	switch repository.Visibility {
+	case "public":
+		grants = append(grants, accessGrant{Source: "public repository", Role: "read"})
+	case "internal":
+		if member {
+			grants = append(grants, accessGrant{Source: "internal repository", Role: "read"})
+		}
+	}
+
+	repoGrants, err := listRepoGrants(hostname, org, repo)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, grant := range repoGrants {
+		switch {
+		case grant.GranteeType == "user" && strings.EqualFold(grant.Grantee, login):
+			grants = append(grants, accessGrant{Source: "direct collaborator", Role: grant.Role})
+		case grant.GranteeType == "team":
+			// Team membership includes the members of child teams
+			_, _, err := ghAPI(hostname, "orgs/"+org+"/teams/"+grant.Grantee+"/memberships/"+login)
+			if err == nil {
+				grants = append(grants, accessGrant{Source: "team " + grant.Grantee, Role: grant.Role})
+			} else if !isNotFoundError(err) {
+				return nil, "", fmt.Errorf("team membership lookup failed for %s: %w", grant.Grantee, err)
+			}
+		}
+	}
+
+	var permission struct {
+		RoleName string `json:"role_name"`
+	}
+	reported := ""
+	if response, _, err := ghAPI(hostname, "repos/"+org+"/"+repo+"/collaborators/"+login+"/permission"); err == nil {
+		if json.Unmarshal(response.Bytes(), &permission) == nil {
+			reported = permission.RoleName
+		}
+	}
+	return grants, reported, nil
+}
+
+// teamGrants returns the roles a team holds on a repository, directly or
+// through its parent teams
+func teamGrants(hostname, org, repo, slug string) ([]accessGrant, error) {
+	repoGrants, err := listRepoGrants(hostname, org, repo)
+	if err != nil {
+		return nil, err
+	}
+	teamRoles := map[string]string{}
+	for _, grant := range repoGrants {
+		if grant.GranteeType == "team" {
+			teamRoles[grant.Grantee] = grant.Role
+		}
+	}
+
+	var grants []accessGrant
+	seen := map[string]bool{}
+	for current := slug; current != "" && !seen[current]; {
+		seen[current] = true
+		var team struct {
+			Parent *struct {
+				Slug string `json:"slug"`
+			} `json:"parent"`
+		}
+		response, stderr, err := ghAPI(hostname, "orgs/"+org+"/teams/"+current)
+		if err != nil {
+			return nil, fmt.Errorf("team lookup failed for %s: %w (%s)", current, err, stderr.String())
+		}
+		if err := json.Unmarshal(response.Bytes(), &team); err != nil {
+			return nil, err
+		}
+
+		if role, ok := teamRoles[current]; ok {
+			source := "team " + current
+			if current != slug {
+				source = "parent team " + current
+			}
+			grants = append(grants, accessGrant{Source: source, Role: role})
+		}
+		current = ""
+		if team.Parent != nil {
+			current = team.Parent.Slug
+		}
+	}
+	return grants, nil
+}
+
+// resolveEffectiveAccess combines grants into the highest base role and the
+// union of the fine-grained permissions their custom roles add
+func resolveEffectiveAccess(grants []accessGrant, roles []customRole) effectiveAccess {
+	customRoles := map[string]customRole{}
+	for _, role := range roles {
+		customRoles[roleNameKey(role.Name)] = role
+	}
+
+	var access effectiveAccess
+	var permissions []string
+	for _, grant := range grants {
+		base := grant.Role
+		if role, ok := customRoles[roleNameKey(grant.Role)]; ok && roleKind(grant.Role) == roleKindCustom {
+			base = role.BaseRole
+			permissions = append(permissions, role.Permissions...)
+		}
+		switch {
+		case accessRank(base) > accessRank(access.BaseRole):
+			access.BaseRole = base
+			access.Sources = []string{grant.Source}
+		case base == access.BaseRole && base != "":
+			access.Sources = append(access.Sources, grant.Source)
+		}
+	}
+	access.Permissions = uniqueStrings(permissions)
+	sort.Strings(access.Permissions)
+	return access
+}
+
+func buildSimulateReplicationCommand(opts options, org, repo string) string {
+	cmd := "gh custom-roles simulate"
+
+	if opts.hostname != "" {
+		cmd += " --hostname " + shellQuote(opts.hostname)
+	}
+	cmd += " --org " + shellQuote(org)
+	cmd += " --repo " + shellQuote(repo)
+	if opts.subjectUser != "" {
+		cmd += " --user " + shellQuote(opts.subjectUser)
+	} else {
+		cmd += " --team " + shellQuote(opts.team)
+	}
+
+	return cmd
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// statsTopCount is how many role names and permissions the rankings show
+const statsTopCount = 10
+
+// rankedValue is a value with the number of times it occurs
+type rankedValue struct {
+	Value string
+	Count int
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize custom role usage across organizations",
+	RunE:  runStats,
+}
+
+func runStats(_ *cobra.Command, _ []string) error {
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	if err := selectTargets(); err != nil {
+		return err
+	}
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, opts.allOrgs); err != nil {
+		return err
+	}
+
+	if err := resolveEnterprise(); err != nil {
+		return err
+	}
+
+	orgs, err := resolveOrganizations(opts)
+	if err != nil {
+		return err
+	}
+	if len(orgs) == 0 {
+		return errors.New("no organizations provided")
+	}
+
+	// Validate concurrency and delay bounds
+	if err := validatePacing(opts); err != nil {
+		return err
+	}
+
+	inventory, err := collectCustomRoles(opts, orgs)
+	if err != nil {
+		return err
+	}
+
+	orgsWithRoles := map[string]bool{}
+	var names []string
+	var permissions []string
+	for _, role := range inventory.Roles {
+		orgsWithRoles[role.Org] = true
+		names = append(names, role.Role.Name)
+		permissions = append(permissions, role.Role.Permissions...)
+	}
+
+	pterm.Println()
+	pterm.DefaultSection.Println("Custom role statistics")
+	pterm.Info.Printfln("Organizations read: %d", len(inventory.Orgs))
+	pterm.Info.Printfln("With custom roles: %d", len(orgsWithRoles))
+	pterm.Info.Printfln("Without custom roles: %d", len(inventory.Orgs)-len(orgsWithRoles))
+	pterm.Info.Printfln("Custom roles: %d", len(inventory.Roles))
+	if opts.managedOnly {
+		pterm.Info.Printfln("Left out by --managed-only: %d", inventory.Unmanaged)
+	} else {
+		pterm.Info.Printfln("Managed by gh-custom-roles: %d", countManaged(inventory.Roles))
+	}
+	if len(inventory.Roles) > 0 {
+		pterm.Info.Printfln("Average permissions per role: %.1f", float64(len(permissions))/float64(len(inventory.Roles)))
+	}
+
+	if err := printRanking("Most common role names", "Role Name", "Organizations", rankValues(names)); err != nil {
+		return err
+	}
+	if err := printRanking("Most frequently granted permissions", "Permission", "Roles", rankValues(permissions)); err != nil {
+		return err
+	}
+
+	if inventory.ErrorCount > 0 {
+		pterm.Println()
+		pterm.Error.Printfln("✗ Errors: %d", inventory.ErrorCount)
+	}
+
+	// Display command for replication
+	printReplicationTip("this report", buildStatsReplicationCommand(opts))
+
+	if inventory.ErrorCount > 0 {
+		return fmt.Errorf("completed with %d errors", inventory.ErrorCount)
+	}
+	return nil
+}
+
+// rankValues counts each value and returns them most frequent first
+func rankValues(values []string) []rankedValue {
+	counts := map[string]int{}
+	for _, value := range values {
+		counts[value]++
+	}
+	ranked := make([]rankedValue, 0, len(counts))
+	for value, count := range counts {
+		ranked = append(ranked, rankedValue{Value: value, Count: count})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+		return ranked[i].Value < ranked[j].Value
+	})
+	return ranked
+}
+
+func printRanking(title, valueHeader, countHeader string, ranked []rankedValue) error {
+	pterm.Println()
+	pterm.DefaultSection.WithLevel(2).Println(title)
+	if len(ranked) == 0 {
+		pterm.Info.Println("None found.")
+		return nil
+	}
+	if len(ranked) > statsTopCount {
+		ranked = ranked[:statsTopCount]
+	}
+	data := pterm.TableData{{valueHeader, countHeader}}
+	for _, entry := range ranked {
+		data = append(data, []string{entry.Value, fmt.Sprintf("%d", entry.Count)})
+	}
+	return pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+}
+
+func buildStatsReplicationCommand(opts options) string {
+	cmd := "gh custom-roles stats"
+
+	if opts.hostname != "" {
+		cmd += " --hostname " + shellQuote(opts.hostname)
+	}
+	cmd += targetFlags(opts)
+	cmd += pacingFlags(opts)
+
+	return cmd
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+// Event names written by --stream besides the result statuses
+const (
+	eventStarted  = "started"
+	eventQueued   = "queued"
+	eventChecking = "checking"
+	eventFinished = "finished"
+)
+
+// streamEvent is one line of the --stream NDJSON output
+type streamEvent struct {
+	Time     time.Time      `json:"time"`
+	Command  string         `json:"command"`
+	Event    string         `json:"event"`
+	Host     string         `json:"host,omitempty"`
+	Target   string         `json:"target,omitempty"`
+	Category string         `json:"category,omitempty"`
+	Message  string         `json:"message,omitempty"`
+	Counts   map[string]int `json:"counts,omitempty"`
+}
+
+// eventStream writes run events as newline-delimited JSON as they happen.
+// Every event is written straight through, so a dashboard tailing the file
+// sees it immediately.
+type eventStream struct {
+	mu      sync.Mutex
+	out     io.Writer
+	file    *os.File
+	command string
+}
+
+// runEvents is the stream opened by --stream, or nil when streaming is off
+var runEvents *eventStream
+
+// openEventStream starts streaming events for command to path, or to stdout
+// when path is "-". Streaming to stdout moves all other output to stderr so
+// the stream stays parseable.
+func openEventStream(path, command string) error {
+	stream := &eventStream{out: os.Stdout, command: command}
+	if path == "-" {
+		pterm.SetDefaultOutput(os.Stderr)
+	} else {
+		// The hosts of a multi-host run append to the file the run created
+		flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if runHost != "" {
+			flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		}
+		file, err := os.OpenFile(filepath.Clean(path), flags, 0o666)
+		if err != nil {
+			return err
+		}
+		stream.out, stream.file = file, file
+	}
+	runEvents = stream
+	return nil
+}
+
+// closeEventStream closes the --stream file, if any
+func closeEventStream() {
+	if runEvents != nil && runEvents.file != nil {
+		_ = runEvents.file.Close()
+	}
+}
+
+// emitEvent writes an event when streaming is on. Write errors are ignored
+// so a closed pipe never interrupts a rollout.
+func emitEvent(event streamEvent) {
+	if runEvents == nil {
+		return
+	}
+	runEvents.mu.Lock()
+	defer runEvents.mu.Unlock()
+	event.Time = time.Now().UTC()
+	event.Command = runEvents.command
+	event.Host = opts.hostname
+	event.Message = redactSecrets(event.Message)
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	_, _ = runEvents.out.Write(append(data, '\n'))
+}
+
+// streamQueued forwards targets from queue and emits a queued event for each
+func streamQueued(queue <-chan string) <-chan string {
+	if runEvents == nil {
+		return queue
+	}
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		for target := range queue {
+			emitEvent(streamEvent{Event: eventQueued, Target: target})
+			out <- target
+		}
+	}()
+	return out
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pterm/pterm"
+)
+
+// Result statuses recorded for each target of a run
+const (
+	statusSucceeded = "succeeded"
+	statusSkipped   = "skipped"
+	statusFailed    = "failed"
+)
+
+// targetResult records the outcome of a run for a single target
+type targetResult struct {
+	// Host is set in multi-host runs
+	Host     string `json:"host,omitempty"`
+	Target   string `json:"target"`
+	Status   string `json:"status"`
+	Category string `json:"category,omitempty"`
+	Message  string `json:"message"`
+}
+
+// issueBodyLimit is the most characters GitHub accepts in an issue or comment
+// body
+const issueBodyLimit = 65536
+
+// summaryDetail is a labeled value shown at the top of a run summary
+type summaryDetail struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// publishRunSummary writes the run summary to every configured destination:
+// the local run history, the GitHub Actions step summary and, with
+// --report-issue and --report-gist, a GitHub issue and a secret gist
+func publishRunSummary(opts options, title string, details []summaryDetail, results *runResults) {
+	if opts.shard != "" {
+		details = append(details, summaryDetail{Label: "Shard", Value: opts.shard})
+	}
+	if hostColumns() {
+		details = append([]summaryDetail{{Label: "Host", Value: opts.hostname}}, details...)
+	}
+
+	record := newRunRecord(opts, title, details, results)
+	if err := saveRunRecord(&record); err != nil {
+		pterm.Warning.Printfln("Failed to save run history: %v", err)
+	} else {
+		pterm.Info.Printfln("Run saved as %s (view it with: gh custom-roles runs show %s)", record.ID, record.ID)
+	}
+
+	if err := writeStepSummary(title, details, results); err != nil {
+		pterm.Warning.Printfln("Failed to write GitHub Actions step summary: %v", err)
+	}
+
+	if opts.reportIssue != "" {
+		url, err := reportToIssue(opts.hostname, opts.reportIssue, title, buildMarkdownSummary(title, details, results, issueBodyLimit))
+		if err != nil {
+			pterm.Warning.Printfln("Failed to report run summary to %s: %v", opts.reportIssue, err)
+		} else {
+			pterm.Info.Printfln("Run summary reported to %s", url)
+		}
+	}
+
+	if opts.reportGist {
+		url, err := reportToGist(opts.hostname, title, buildMarkdownSummary(title, details, results, 0), record)
+		if err != nil {
+			pterm.Warning.Printfln("Failed to upload run summary gist: %v (the token needs the gist scope: gh auth refresh -s gist)", err)
+		} else {
+			pterm.Info.Printfln("Run summary uploaded to %s", url)
+		}
+	}
+}
+
+// reportToGist uploads the markdown and JSON run summary as a secret gist and
+// returns its URL. Secret gists are unlisted, not private: anyone with the
+// URL can read them.
+func reportToGist(hostname, title, markdown string, record runRecord) (string, error) {
+	payload, err := gistPayload(title, markdown, record)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := restClient(hostname)
+	if err != nil {
+		return "", err
+	}
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := client.Post("gists", bytes.NewReader(payload), &created); err != nil {
+		return "", err
+	}
+	return created.HTMLURL, nil
+}
+
+// gistPayload returns the request body that creates the run summary gist,
+// with secrets scrubbed from both files
+func gistPayload(title, markdown string, record runRecord) ([]byte, error) {
+	// Redacting the encoded JSON could swallow closing quotes, so the record
+	// is scrubbed before it is encoded
+	data, err := json.MarshalIndent(redactRecord(record), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	name := "run-summary"
+	if record.ID != "" {
+		name += "-" + record.ID
+	}
+
+	type gistFile struct {
+		Content string `json:"content"`
+	}
+	return json.Marshal(struct {
+		Description string              `json:"description"`
+		Public      bool                `json:"public"`
+		Files       map[string]gistFile `json:"files"`
+	}{
+		Description: redactSecrets(title),
+		Public:      false,
+		Files: map[string]gistFile{
+			name + ".md":   {Content: redactSecrets(markdown)},
+			name + ".json": {Content: string(data)},
+		},
+	})
+}
+
+// redactRecord returns a copy of a run record with secrets scrubbed from its
+// free-text fields
+func redactRecord(record runRecord) runRecord {
+	record.Title = redactSecrets(record.Title)
+	details := make([]summaryDetail, len(record.Details))
+	for i, detail := range record.Details {
+		details[i] = summaryDetail{Label: detail.Label, Value: redactSecrets(detail.Value)}
+	}
+	record.Details = details
+	results := make([]targetResult, len(record.Results))
+	for i, result := range record.Results {
+		result.Message = redactSecrets(result.Message)
+		results[i] = result
+	}
+	record.Results = results
+	return record
+}
+
+// writeStepSummary appends a markdown run summary to the file named by
+// GITHUB_STEP_SUMMARY so results show up on the GitHub Actions run page. It
+// does nothing outside of Actions.
+func writeStepSummary(title string, details []summaryDetail, results *runResults) error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(redactSecrets(buildMarkdownSummary(title, details, results, 0)))
+	return err
+}
+
+// buildMarkdownSummary renders the run summary as markdown. With a limit, the
+// results table is cut short so the redacted summary stays within that many
+// characters, and a closing line counts the rows left out.
+func buildMarkdownSummary(title string, details []summaryDetail, results *runResults, limit int) string {
+	var builder strings.Builder
+	builder.WriteString("## " + title + "\n\n")
+	for _, detail := range details {
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", detail.Label, escapeMarkdown(detail.Value)))
+	}
+
+	builder.WriteString(fmt.Sprintf("\n✓ %d succeeded · ⚠ %d skipped · ✗ %d failed\n\n",
+		results.Count(statusSucceeded), results.Count(statusSkipped), results.Count(statusFailed)))
+	stats := apiUsage.Snapshot()
+	builder.WriteString(fmt.Sprintf("Took %s · API requests: %s · %d retries · %s rate-limited\n\n",
+		results.Elapsed(), stats, stats.Retries, stats.RateLimited()))
+	if results.Aborted() {
+		builder.WriteString(fmt.Sprintf("**Aborted** (%s); %d targets were not processed.\n\n", failureLimitFlag(opts), results.Unprocessed()))
+	}
+	if categories := results.Categories(statusSkipped); len(categories) > 0 {
+		builder.WriteString("**Warnings by reason**\n\n")
+		for _, category := range categories {
+			builder.WriteString(fmt.Sprintf("- %s: %d — %s\n", escapeMarkdown(category.Name), category.Count, escapeMarkdown(formatCategoryTargets(category))))
+		}
+		builder.WriteString("\n")
+	}
+	if categories := results.Categories(statusFailed); len(categories) > 0 {
+		builder.WriteString("**Errors by cause**\n\n")
+		for _, category := range categories {
+			builder.WriteString(fmt.Sprintf("- %s: %d — %s\n", escapeMarkdown(category.Name), category.Count, escapeMarkdown(formatCategoryTargets(category))))
+		}
+		builder.WriteString("\n")
+	}
+
+	sorted := results.Recent()
+	if total := results.Total(); len(sorted) < total {
+		builder.WriteString(fmt.Sprintf("Showing the last %d of %d results.\n\n", len(sorted), total))
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Target < sorted[j].Target
+	})
+
+	if hostColumns() {
+		builder.WriteString("| Host | Target | Result | Details |\n")
+		builder.WriteString("|------|--------|--------|---------|\n")
+	} else {
+		builder.WriteString("| Target | Result | Details |\n")
+		builder.WriteString("|--------|--------|---------|\n")
+	}
+	// Room is kept for the line that counts the rows left out
+	length := utf8.RuneCountInString(redactSecrets(builder.String())) + 100
+	for i, result := range sorted {
+		row := fmt.Sprintf("| %s | %s | %s |\n", escapeMarkdown(result.Target), result.Status, escapeMarkdown(result.Message))
+		if hostColumns() {
+			row = "| " + escapeMarkdown(result.Host) + " " + row
+		}
+		length += utf8.RuneCountInString(redactSecrets(row))
+		if limit > 0 && length > limit {
+			builder.WriteString(fmt.Sprintf("\n… %d more (see gist/run record)\n", len(sorted)-i))
+			break
+		}
+		builder.WriteString(row)
+	}
+	builder.WriteString("\n")
+	return builder.String()
+}
+
+// escapeMarkdown keeps values from breaking markdown table rows
+func escapeMarkdown(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	value = strings.ReplaceAll(value, "\r", "")
+	return strings.ReplaceAll(value, "\n", " ")
+}
+
+var issueReferencePattern = regexp.MustCompile(`^([\w.-]+)/([\w.-]+)(?:#(\d+))?$`)
+
+// reportToIssue posts the run summary to GitHub. A reference of the form
+// owner/repo opens a new issue; owner/repo#123 adds a comment to issue 123.
+// It returns the URL of the created issue or comment.
+func reportToIssue(hostname, reference, title, body string) (string, error) {
+	args, err := issueRequestArgs(reference, title, body)
+	if err != nil {
+		return "", err
+	}
+	response, stderr, err := ghAPI(hostname, args...)
+	if err != nil {
+		return "", fmt.Errorf("%w (%s)", err, stderr.String())
+	}
+
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal(response.Bytes(), &created); err != nil {
+		return "", err
+	}
+	return created.HTMLURL, nil
+}
+
+// issueRequestArgs returns the gh api arguments that open an issue or add a
+// comment with the run summary, with secrets scrubbed from the title and body
+func issueRequestArgs(reference, title, body string) ([]string, error) {
+	match := issueReferencePattern.FindStringSubmatch(strings.TrimSpace(reference))
+	if match == nil {
+		return nil, errors.New("expected owner/repo or owner/repo#number")
+	}
+	owner, repo, number := match[1], match[2], match[3]
+
+	args := []string{"-X", "POST"}
+	if number != "" {
+		return append(args, "repos/"+owner+"/"+repo+"/issues/"+number+"/comments", "-f", "body="+redactSecrets(body)), nil
+	}
+	return append(args, "repos/"+owner+"/"+repo+"/issues", "-f", "title="+redactSecrets(title), "-f", "body="+redactSecrets(body)), nil
+}
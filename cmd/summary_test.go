@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/pterm/pterm"
+)
+
+func TestIssueSummaryFitsBodyLimit(t *testing.T) {
+	pterm.SetDefaultOutput(io.Discard)
+	t.Cleanup(func() { pterm.SetDefaultOutput(os.Stdout) })
+
+	results := newRunResults(recentResultLimit)
+	for i := range recentResultLimit {
+		results.Record(targetResult{
+			Target:  fmt.Sprintf("acme-%04d", i),
+			Status:  statusFailed,
+			Message: strings.Repeat("request failed ", 10),
+		})
+	}
+	details := []summaryDetail{{Label: "Role", Value: "Developer"}}
+
+	full := buildMarkdownSummary("Create custom role", details, results, 0)
+	if utf8.RuneCountInString(full) <= issueBodyLimit {
+		t.Fatalf("summary without a limit is %d characters, want more than %d for this test", utf8.RuneCountInString(full), issueBodyLimit)
+	}
+
+	body := buildMarkdownSummary("Create custom role", details, results, issueBodyLimit)
+	if length := utf8.RuneCountInString(body); length > issueBodyLimit {
+		t.Errorf("issue summary is %d characters, want at most %d", length, issueBodyLimit)
+	}
+	kept := strings.Count(body, "| acme-")
+	if want := fmt.Sprintf("… %d more (see gist/run record)", recentResultLimit-kept); !strings.Contains(body, want) {
+		t.Errorf("issue summary does not end with %q", want)
+	}
+	if !strings.Contains(body, "| acme-0000 |") {
+		t.Errorf("issue summary dropped the first rows of the table")
+	}
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+// targetStream delivers target organizations to workers as they are
+// resolved instead of materializing the full list first. For enterprise
+// targets, later pages are fetched while earlier organizations are processed.
+type targetStream struct {
+	// Orgs yields each target organization once and is closed when
+	// resolution finishes or fails
+	Orgs <-chan string
+	// First is the first target organization, known when the stream opens
+	First string
+	// Total is the number of target organizations reported by the resolver
+	Total int
+
+	err chan error
+}
+
+// Err returns the error that stopped resolution, if any. It must only be
+// called after Orgs has been drained.
+func (t *targetStream) Err() error {
+	return <-t.err
+}
+
+// Collect drains the stream into a slice for flows that need every target
+// before they can continue
+func (t *targetStream) Collect() ([]string, error) {
+	var orgs []string
+	for org := range t.Orgs {
+		orgs = append(orgs, org)
+	}
+	return orgs, t.Err()
+}
+
+// openTargetStream starts resolving the target organizations. Single orgs
+// and CSV files are resolved immediately; enterprise organizations are sent
+// page by page as they arrive. The first enterprise page is awaited so that
+// First and Total are known before the stream is returned.
+func openTargetStream(opts options) (*targetStream, error) {
+	// A shard or filter needs the whole enterprise list to report its own total
+	if !opts.allOrgs || opts.shard != "" || newOrgFilter(opts).active() {
+		orgs, err := resolveOrganizations(opts)
+		if err != nil {
+			return nil, err
+		}
+		return newSliceTargetStream(orgs), nil
+	}
+
+	pages := streamOrganizations(opts.hostname, opts.enterprise, orgFilter{})
+	first, ok := <-pages
+	if !ok {
+		return newSliceTargetStream(nil), nil
+	}
+	if first.Err != nil {
+		return nil, first.Err
+	}
+
+	orgs := make(chan string)
+	stream := &targetStream{Orgs: orgs, Total: first.Total, err: make(chan error, 1)}
+	if len(first.Logins) > 0 {
+		stream.First = first.Logins[0]
+	}
+
+	go func() {
+		defer close(orgs)
+		seen := map[string]bool{}
+		send := func(page organizationPage) {
+			for _, org := range page.Logins {
+				if org == "" || seen[org] {
+					continue
+				}
+				seen[org] = true
+				orgs <- org
+			}
+		}
+
+		send(first)
+		for page := range pages {
+			if page.Err != nil {
+				stream.err <- page.Err
+				return
+			}
+			send(page)
+		}
+		stream.err <- nil
+	}()
+
+	return stream, nil
+}
+
+// newSliceTargetStream wraps an already resolved list of organizations
+func newSliceTargetStream(orgs []string) *targetStream {
+	orgs = uniqueStrings(orgs)
+	queue := make(chan string, len(orgs))
+	for _, org := range orgs {
+		queue <- org
+	}
+	close(queue)
+
+	stream := &targetStream{Orgs: queue, Total: len(orgs), err: make(chan error, 1)}
+	if len(orgs) > 0 {
+		stream.First = orgs[0]
+	}
+	stream.err <- nil
+	return stream
+}
+
+// parseShard parses --shard as INDEX/COUNT, such as 2/5, with INDEX counted
+// from 1
+func parseShard(value string) (int, int, error) {
+	indexText, countText, ok := strings.Cut(strings.TrimSpace(value), "/")
+	index, indexErr := strconv.Atoi(indexText)
+	count, countErr := strconv.Atoi(countText)
+	if !ok || indexErr != nil || countErr != nil || count < 1 || index < 1 || index > count {
+		return 0, 0, fmt.Errorf("invalid --shard %q: expected INDEX/COUNT such as 2/5", value)
+	}
+	return index, count, nil
+}
+
+// shardOrganizations keeps the organizations that belong to the --shard
+// slice. Organizations are assigned by a hash of their login rather than
+// their position, so parallel jobs agree on the partition even if the
+// enterprise gains or loses organizations between their listings.
+func shardOrganizations(opts options, orgs []string) []string {
+	if opts.shard == "" {
+		return orgs
+	}
+	// --shard is validated before any command runs
+	index, count, _ := parseShard(opts.shard)
+
+	var shard []string
+	for _, org := range orgs {
+		if shardOf(org, count) == index {
+			shard = append(shard, org)
+		}
+	}
+	pterm.Info.Printfln("Shard %d/%d: %d of %d organizations", index, count, len(shard), len(orgs))
+	return shard
+}
+
+// shardOf returns the 1-based shard an organization belongs to
+func shardOf(org string, count int) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(strings.ToLower(org)))
+	return int(hash.Sum32()%uint32(count)) + 1
+}
+
+// orgFilterDateLayout is the date format of --orgs-created-after
+const orgFilterDateLayout = "2006-01-02"
+
+// orgFilter narrows enterprise organizations by properties read in the same
+// GraphQL query that lists them
+type orgFilter struct {
+	CreatedAfter time.Time
+	MinRepos     int
+}
+
+// newOrgFilter builds the filter from the --orgs-created-after and
+// --min-repos flags, which are validated before any command runs
+func newOrgFilter(opts options) orgFilter {
+	filter, _ := parseOrgFilter(opts)
+	return filter
+}
+
+func parseOrgFilter(opts options) (orgFilter, error) {
+	filter := orgFilter{MinRepos: opts.minRepos}
+	if filter.MinRepos < 0 {
+		return filter, fmt.Errorf("--min-repos must be non-negative (got %d)", opts.minRepos)
+	}
+	if opts.orgsCreatedAfter != "" {
+		createdAfter, err := time.Parse(orgFilterDateLayout, strings.TrimSpace(opts.orgsCreatedAfter))
+		if err != nil {
+			return filter, fmt.Errorf("invalid --orgs-created-after %q: expected a date such as 2024-01-01", opts.orgsCreatedAfter)
+		}
+		filter.CreatedAfter = createdAfter
+	}
+	return filter, nil
+}
+
+func (f orgFilter) active() bool {
+	return !f.CreatedAfter.IsZero() || f.MinRepos > 0
+}
+
+// match reports whether an organization created at createdAt with repos
+// repositories passes the filter. The creation date is inclusive.
+func (f orgFilter) match(createdAt time.Time, repos int) bool {
+	if !f.CreatedAfter.IsZero() && createdAt.Before(f.CreatedAfter) {
+		return false
+	}
+	return repos >= f.MinRepos
+}
+
+func (f orgFilter) String() string {
+	var parts []string
+	if !f.CreatedAfter.IsZero() {
+		parts = append(parts, "created on or after "+f.CreatedAfter.Format(orgFilterDateLayout))
+	}
+	if f.MinRepos > 0 {
+		parts = append(parts, fmt.Sprintf("at least %d repositories", f.MinRepos))
+	}
+	return strings.Join(parts, " and ")
+}
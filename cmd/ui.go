@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/pterm/pterm"
+)
+
+// noTip hides the replication command tip (set by --no-tip)
+var noTip bool
+
+// accessible replaces spinners, progress bars, and interactive widgets with
+// plain sequential output and numbered prompts (set by --accessible)
+var accessible bool
+
+// noPrompts makes every prompt fail instead of waiting for an answer, for
+// commands that must never block a scheduled run
+var noPrompts bool
+
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// checkPrompt returns an error when the value asked for by message cannot be
+// prompted for: the command never prompts, or stdin is not a terminal for the
+// interactive widgets. Accessible prompts read plain lines, so they still
+// accept answers piped to stdin.
+func checkPrompt(message string) error {
+	if runHost != "" {
+		return fmt.Errorf("cannot prompt for %q: hosts of a multi-host run cannot prompt; pass the value with its flag", message)
+	}
+	if noPrompts {
+		return fmt.Errorf("cannot prompt for %q: this command never prompts; pass the value with its flag", message)
+	}
+	if !accessible && !term.IsTerminal(os.Stdin) {
+		return fmt.Errorf("cannot prompt for %q: stdin is not a terminal; pass the value with its flag", message)
+	}
+	return nil
+}
+
+// readLine prints a prompt and reads a single line from stdin
+func readLine(message string) (string, error) {
+	pterm.Print(message + ": ")
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func promptText(message string) (string, error) {
+	if err := checkPrompt(message); err != nil {
+		return "", err
+	}
+	if accessible {
+		return readLine(message)
+	}
+	return pterm.DefaultInteractiveTextInput.Show(message)
+}
+
+func promptSelect(message string, options []string, defaultOption string) (string, error) {
+	if err := checkPrompt(message); err != nil {
+		return "", err
+	}
+	if !accessible {
+		selectInput := pterm.DefaultInteractiveSelect.WithOptions(options)
+		if defaultOption != "" {
+			selectInput = selectInput.WithDefaultOption(defaultOption)
+		}
+		return selectInput.Show(message)
+	}
+
+	pterm.Println(message)
+	defaultIndex := 0
+	for i, option := range options {
+		if option == defaultOption {
+			defaultIndex = i
+		}
+		pterm.Printfln("  %d. %s", i+1, option)
+	}
+	for {
+		answer, err := readLine(fmt.Sprintf("Enter a number (press enter for %d)", defaultIndex+1))
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			return options[defaultIndex], nil
+		}
+		index, err := strconv.Atoi(answer)
+		if err == nil && index >= 1 && index <= len(options) {
+			return options[index-1], nil
+		}
+		pterm.Printfln("Please enter a number between 1 and %d.", len(options))
+	}
+}
+
+// promptMultiselect asks for any number of options, with the defaults checked
+// to begin with
+func promptMultiselect(message string, options []string, defaults []string) ([]string, error) {
+	if err := checkPrompt(message); err != nil {
+		return nil, err
+	}
+	if !accessible {
+		return pterm.DefaultInteractiveMultiselect.
+			WithOptions(options).
+			WithDefaultOptions(defaults).
+			WithFilter(true).
+			WithMaxHeight(10).
+			Show(message + " (Type to filter, ↑↓ to navigate, Enter to toggle, Tab to confirm)")
+	}
+
+	checked := map[string]bool{}
+	for _, option := range defaults {
+		checked[option] = true
+	}
+	pterm.Println(message)
+	for i, option := range options {
+		if checked[option] {
+			pterm.Printfln("  %d. [x] %s", i+1, option)
+		} else {
+			pterm.Printfln("  %d. %s", i+1, option)
+		}
+	}
+	prompt := "Enter numbers separated by commas"
+	if len(defaults) > 0 {
+		prompt += " (press enter to keep the checked items)"
+	}
+	for {
+		answer, err := readLine(prompt)
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" && len(defaults) > 0 {
+			return defaults, nil
+		}
+		selected, ok := parseNumberedSelection(answer, options)
+		if ok {
+			return selected, nil
+		}
+		pterm.Printfln("Please enter numbers between 1 and %d separated by commas.", len(options))
+	}
+}
+
+func parseNumberedSelection(answer string, options []string) ([]string, bool) {
+	var selected []string
+	for _, item := range strings.Split(answer, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		index, err := strconv.Atoi(item)
+		if err != nil || index < 1 || index > len(options) {
+			return nil, false
+		}
+		selected = append(selected, options[index-1])
+	}
+	return selected, true
+}
+
+// confirmRun asks before a command starts changing roles or grants. A host of
+// a multi-host run that was confirmed for all hosts does not ask again.
+func confirmRun(message string) (bool, error) {
+	if runConfirmed {
+		return true, nil
+	}
+	return promptConfirm(message)
+}
+
+func promptConfirm(message string) (bool, error) {
+	if err := checkPrompt(message); err != nil {
+		return false, err
+	}
+	if !accessible {
+		return pterm.DefaultInteractiveConfirm.Show(message)
+	}
+
+	for {
+		answer, err := readLine(message + " (y/n)")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		pterm.Println("Please answer y or n.")
+	}
+}
+
+// progressbarPrinter wraps a pterm progress bar, printing plain start and finish
+// lines instead of a live bar in accessible mode
+type progressbarPrinter struct {
+	bar     *pterm.ProgressbarPrinter
+	title   string
+	total   int
+	current int
+	stopped bool
+}
+
+func startProgressbar(total int, title string) (*progressbarPrinter, error) {
+	p := &progressbarPrinter{title: title, total: total}
+	if accessible {
+		pterm.Printfln("%s (%d total)", title, total)
+		return p, nil
+	}
+
+	bar, err := pterm.DefaultProgressbar.WithTotal(total).WithTitle(title).Start()
+	if err != nil {
+		return nil, err
+	}
+	p.bar = bar
+	return p, nil
+}
+
+func (p *progressbarPrinter) Increment() {
+	if p.stopped {
+		return
+	}
+	p.current++
+	if p.bar != nil {
+		p.bar.Increment()
+	}
+}
+
+func (p *progressbarPrinter) Stop() {
+	if p.stopped {
+		return
+	}
+	p.stopped = true
+	if p.bar != nil {
+		p.bar.Stop()
+		return
+	}
+	pterm.Printfln("%s: finished %d of %d", p.title, p.current, p.total)
+}
+
+// spinnerPrinter wraps a pterm spinner, printing each status as its own line in
+// accessible mode
+type spinnerPrinter struct {
+	printer *pterm.SpinnerPrinter
+}
+
+func startSpinner(text string) (*spinnerPrinter, error) {
+	if accessible {
+		pterm.Println(text)
+		return &spinnerPrinter{}, nil
+	}
+
+	printer, err := pterm.DefaultSpinner.Start(text)
+	if err != nil {
+		return nil, err
+	}
+	return &spinnerPrinter{printer: printer}, nil
+}
+
+func (s *spinnerPrinter) UpdateText(text string) {
+	if s.printer == nil {
+		pterm.Println(text)
+		return
+	}
+	s.printer.UpdateText(text)
+}
+
+func (s *spinnerPrinter) Success(text string) {
+	if s.printer == nil {
+		pterm.Println(text)
+		return
+	}
+	s.printer.Success(text)
+}
+
+func (s *spinnerPrinter) Fail(text string) {
+	if s.printer == nil {
+		pterm.Println(text)
+		return
+	}
+	s.printer.Fail(text)
+}
+
+func (s *spinnerPrinter) Stop() {
+	if s.printer != nil {
+		s.printer.Stop()
+	}
+}
+
+// printReplicationTip shows the command that repeats this run without
+// prompts. The tip is left out with --no-tip and when stdout is not a
+// terminal, where it is only noise in logs; step summaries and issue reports
+// still include the command.
+func printReplicationTip(subject, command string) {
+	if noTip || !term.IsTerminal(os.Stdout) {
+		return
+	}
+	pterm.Println()
+	pterm.FgMagenta.Printfln("💡 Tip: To replicate %s without the interactive process, use:", subject)
+	pterm.Println()
+	pterm.Println(command)
+	pterm.Println()
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+var viewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Show the definition of a custom repository role in an organization",
+	Args:  cobra.NoArgs,
+	RunE:  runView,
+}
+
+func init() {
+	// View command flags
+	viewCmd.Flags().StringVarP(&opts.roleName, "role-name", "n", "", "Name of the custom role to show")
+	viewCmd.Flags().StringVar(&opts.viewFormat, "format", formatTable, "Output format: table, json, or yaml (json and yaml use the create --from-file format)")
+	viewCmd.Flags().StringVar(&opts.outputPath, "output", "", "Write json or yaml output to this file instead of stdout")
+}
+
+func runView(_ *cobra.Command, _ []string) error {
+	if err := validateStructuredFormat(opts); err != nil {
+		return err
+	}
+
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	if opts.allOrgs || len(opts.orgsCSVPaths) > 0 || opts.orgsFromCmd != "" {
+		return errors.New("view shows a role in a single organization; use --org")
+	}
+	if opts.org == "" {
+		opts.org, err = promptText("Organization name")
+		if err != nil {
+			return err
+		}
+	}
+	org := normalizeOrg(opts.org)
+	if org == "" {
+		return errors.New("organization name is required")
+	}
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, false); err != nil {
+		return err
+	}
+
+	roles, err := listCustomRoles(opts.hostname, org)
+	if err != nil {
+		return err
+	}
+	if len(roles) == 0 {
+		return fmt.Errorf("organization %s has no custom roles", org)
+	}
+	if opts.roleName == "" {
+		names := make([]string, 0, len(roles))
+		for _, role := range roles {
+			names = append(names, role.Name)
+		}
+		opts.roleName, err = promptSelect("Select role to view", names, "")
+		if err != nil {
+			return err
+		}
+	}
+	role := matchRole(roles, opts.roleName)
+	if role == nil {
+		return fmt.Errorf("organization %s has no role named %s", org, opts.roleName)
+	}
+	if excludedByManagedOnly(*role) {
+		return fmt.Errorf("role %s in %s is not managed by gh-custom-roles; drop --managed-only to view it", role.Name, org)
+	}
+
+	// The definition is written in the format create --from-file reads, so
+	// it can be stored next to role manifests and diffed against them
+	if opts.outputFormat != formatTable {
+		definition := roleFile{
+			Name:        role.Name,
+			Description: role.Description,
+			BaseRole:    role.BaseRole,
+			Permissions: append([]string{}, slices.Sorted(slices.Values(role.Permissions))...),
+		}
+		if err := exportStructured(opts, definition); err != nil {
+			return fmt.Errorf("failed to write role definition: %w", err)
+		}
+		if opts.outputPath != "" {
+			pterm.Success.Printfln("Wrote the definition of %s in %s to %s", role.Name, org, opts.outputPath)
+		}
+		return nil
+	}
+
+	pterm.DefaultSection.Printfln("%s (%s)", role.Name, org)
+	pterm.Info.Printfln("ID: %d", role.ID)
+	pterm.Info.Printfln("Base Role: %s", role.BaseRole)
+	description := role.Description
+	if description == "" {
+		description = "-"
+	}
+	pterm.Info.Printfln("Description: %s", description)
+	if isManaged(*role) {
+		pterm.Info.Println("Managed by gh-custom-roles: yes")
+	}
+	pterm.Info.Printfln("Created: %s", formatRoleTimestamp(role.CreatedAt))
+	pterm.Info.Printfln("Updated: %s", formatRoleTimestamp(role.UpdatedAt))
+
+	pterm.Println()
+	pterm.DefaultSection.WithLevel(2).Printfln("Permissions (%d)", len(role.Permissions))
+	if len(role.Permissions) == 0 {
+		pterm.Info.Println("The role only grants its base role.")
+		return nil
+	}
+
+	// Descriptions come from the cached permission catalog; the names alone
+	// are still worth showing when it cannot be read
+	descriptions := map[string]string{}
+	catalog, err := listFineGrainedPermissions(opts.hostname, org)
+	if err != nil {
+		pterm.Warning.Printfln("Permission descriptions unavailable: %v", err)
+	}
+	for _, permission := range catalog {
+		descriptions[permission.Name] = permission.Description
+	}
+	permissions := slices.Sorted(slices.Values(role.Permissions))
+	data := pterm.TableData{{"Permission", "Description"}}
+	for _, permission := range permissions {
+		data = append(data, []string{permission, descriptions[permission]})
+	}
+	return pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+}
+
+// formatRoleTimestamp shows an API timestamp in local time, or as returned
+// when it cannot be parsed
+func formatRoleTimestamp(value string) string {
+	if value == "" {
+		return "-"
+	}
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return timestamp.Local().Format("2006-01-02 15:04:05")
+}
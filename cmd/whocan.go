@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// permissionGrant is a custom role that grants a permission in an organization
+type permissionGrant struct {
+	Org      string
+	Role     string
+	BaseRole string
+}
+
+var whoCanCmd = &cobra.Command{
+	Use:   "who-can [permission]",
+	Short: "List custom roles that grant a fine-grained permission",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runWhoCan,
+}
+
+func init() {
+	// Who-can command flags
+	whoCanCmd.Flags().BoolVar(&opts.includeAssignees, "assignees", false, "Also list the teams and users holding the matching roles on repositories")
+	whoCanCmd.Flags().StringVar(&opts.whoCanFormat, "format", formatTable, "Output format for assignees: table, csv, json, yaml, or markdown (requires --assignees)")
+	whoCanCmd.Flags().StringVar(&opts.outputPath, "output", "", "Write csv, json, yaml, or markdown output to this file instead of stdout")
+}
+
+func runWhoCan(_ *cobra.Command, args []string) error {
+	if err := validateOutputFormat(opts); err != nil {
+		return err
+	}
+	if opts.outputFormat != formatTable && !opts.includeAssignees {
+		return fmt.Errorf("--format %s requires --assignees", opts.outputFormat)
+	}
+
+	var err error
+	opts.hostname, err = resolveHostname(opts.hostname)
+	if err != nil {
+		return err
+	}
+
+	if err := selectTargets(); err != nil {
+		return err
+	}
+
+	// Validate GitHub environment (GHES version and OAuth scopes)
+	if err := validateGitHubEnvironment(opts.hostname, opts.allOrgs); err != nil {
+		return err
+	}
+
+	if err := resolveEnterprise(); err != nil {
+		return err
+	}
+
+	orgs, err := resolveOrganizations(opts)
+	if err != nil {
+		return err
+	}
+	if len(orgs) == 0 {
+		return errors.New("no organizations provided")
+	}
+
+	permission := ""
+	if len(args) > 0 {
+		permission = strings.TrimSpace(args[0])
+	}
+	permission, err = resolvePermissionName(permission, orgs[0])
+	if err != nil {
+		return err
+	}
+
+	// Validate concurrency and delay bounds
+	if err := validatePacing(opts); err != nil {
+		return err
+	}
+
+	grants, errorCount, err := findPermissionGrants(opts, orgs, permission)
+	if err != nil {
+		return err
+	}
+
+	pterm.Println()
+	pterm.DefaultSection.Println("Roles granting " + permission)
+	if len(grants) == 0 {
+		pterm.Info.Printfln("No custom roles grant %s in the selected organizations.", permission)
+	} else {
+		data := pterm.TableData{{"Organization", "Role", "Base Role"}}
+		for _, grant := range grants {
+			data = append(data, []string{grant.Org, grant.Role, grant.BaseRole})
+		}
+		if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+			return err
+		}
+	}
+
+	if opts.includeAssignees && len(grants) > 0 {
+		roleOrgs := map[string]map[string]bool{}
+		for _, grant := range grants {
+			if roleOrgs[grant.Org] == nil {
+				roleOrgs[grant.Org] = map[string]bool{}
+			}
+			roleOrgs[grant.Org][roleNameKey(grant.Role)] = true
+		}
+		var grantOrgs []string
+		for org := range roleOrgs {
+			grantOrgs = append(grantOrgs, org)
+		}
+		sort.Strings(grantOrgs)
+
+		pterm.Println()
+		assignees, err := scanRepoGrants(opts, grantOrgs, func(grant repoGrant) bool {
+			return roleOrgs[grant.Org][roleNameKey(grant.Role)]
+		})
+		if err != nil {
+			return err
+		}
+
+		if opts.outputFormat == formatTable {
+			pterm.Println()
+			pterm.DefaultSection.WithLevel(2).Println("Assignees")
+			if err := printAssignmentsTable(assignees); err != nil {
+				return err
+			}
+		} else if err := exportAssignments(opts, assignees); err != nil {
+			return fmt.Errorf("failed to export assignees: %w", err)
+		}
+	}
+
+	// Display summary
+	pterm.Println()
+	pterm.DefaultSection.Println("Summary")
+	pterm.Info.Printfln("✓ Roles granting %s: %d", permission, len(grants))
+	if errorCount > 0 {
+		pterm.Error.Printfln("✗ Errors: %d", errorCount)
+	}
+
+	// Display command for replication
+	printReplicationTip("this query", buildWhoCanReplicationCommand(opts, permission))
+
+	if errorCount > 0 {
+		return fmt.Errorf("completed with %d errors", errorCount)
+	}
+	return nil
+}
+
+// resolvePermissionName checks the permission given on the command line
+// against the organization's permission catalog, suggesting the closest
+// names for a typo, or prompts for one from the catalog
+func resolvePermissionName(permission, org string) (string, error) {
+	permissions, err := listFineGrainedPermissions(opts.hostname, org)
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		names = append(names, p.Name)
+	}
+
+	if permission != "" {
+		if !slices.Contains(names, permission) {
+			printPermissionSuggestions([]string{permission}, permissions)
+			return "", fmt.Errorf("unknown permission: %s", permission)
+		}
+		return permission, nil
+	}
+	return promptSelect("Select permission", names, "")
+}
+
+// findPermissionGrants lists every custom role that grants permission in the
+// given organizations, with the number of organizations that could not be read
+func findPermissionGrants(opts options, orgs []string, permission string) ([]permissionGrant, int, error) {
+	inventory, err := collectCustomRoles(opts, orgs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var grants []permissionGrant
+	for _, role := range inventory.Roles {
+		for _, granted := range role.Role.Permissions {
+			if granted == permission {
+				grants = append(grants, permissionGrant{Org: role.Org, Role: role.Role.Name, BaseRole: role.Role.BaseRole})
+				break
+			}
+		}
+	}
+	return grants, inventory.ErrorCount, nil
+}
+
+func buildWhoCanReplicationCommand(opts options, permission string) string {
+	cmd := "gh custom-roles who-can " + shellQuote(permission)
+
+	if opts.hostname != "" {
+		cmd += " --hostname " + shellQuote(opts.hostname)
+	}
+	cmd += targetFlags(opts)
+	if opts.includeAssignees {
+		cmd += " --assignees"
+	}
+	cmd += formatFlags(opts)
+	cmd += pacingFlags(opts)
+
+	return cmd
+}